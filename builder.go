@@ -16,6 +16,10 @@ type builder struct {
 }
 
 func build(plan config.Plan) (*Graph, error) {
+	if err := plan.ExpandAccessPolicies(); err != nil {
+		return nil, err
+	}
+
 	root, ok := plan.CryptexNode(plan.Root)
 	if !ok {
 		return nil, fmt.Errorf("missing root cryptex %q", plan.Root)
@@ -41,6 +45,10 @@ func (b builder) buildGraph(root config.CryptexNode) (*Graph, error) {
 		return nil, err
 	}
 
+	if err := b.checkKeys(b.plan.Root, cptx, root.Edges()); err != nil {
+		return nil, err
+	}
+
 	g, err := NewGraph(cptx)
 	if err != nil {
 		return nil, err
@@ -69,6 +77,9 @@ func (b builder) buildVertex(g *Graph, name string, from *graph.Vertex) error {
 		if err != nil {
 			return err
 		}
+		if err := b.checkKeys(name, cptx, node.Edges()); err != nil {
+			return err
+		}
 
 		env, err := cryptex.Wrap(cptx)
 		if err != nil {
@@ -109,3 +120,43 @@ func (b builder) buildVertex(g *Graph, name string, from *graph.Vertex) error {
 
 	return fmt.Errorf("missing node for edge %q", name)
 }
+
+// checkKeys checks that the x25519-key & hybrid-kem-key secret edges of a box
+// or hybrid-kem cryptex hold the private key for its public key.
+func (b builder) checkKeys(name string, cptx cryptex.Cryptex, edges []string) error {
+	var pub []byte
+	switch cptx := cptx.(type) {
+	case *cryptex.Box:
+		pub = cptx.PublicKey
+	case *cryptex.HybridKEM:
+		pub = cptx.PublicKey
+	default:
+		return nil
+	}
+
+	for _, edge := range edges {
+		node, ok := b.plan.SecretNode(edge)
+		if !ok {
+			continue
+		}
+
+		sec, err := node.Secret()
+		if err != nil {
+			return err
+		}
+
+		var match bool
+		switch sec := sec.(type) {
+		case *secret.X25519Key:
+			match = sec.Match(pub)
+		case *secret.HybridKEMKey:
+			match = sec.Match(pub)
+		default:
+			continue
+		}
+		if !match {
+			return fmt.Errorf("secret %q does not match the public key of %q", edge, name)
+		}
+	}
+	return nil
+}
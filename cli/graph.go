@@ -98,6 +98,23 @@ func nodeDetail(node *vcrypt.Node) (string, error) {
 	}
 	typ = "[" + typ + "]"
 
+	if node.Type() == vcrypt.CryptexNode {
+		cptx, err := node.Cryptex()
+		if err != nil {
+			return "", err
+		}
+		switch c := cptx.(type) {
+		case *cryptex.KDFSecretBox:
+			cmnt = strings.TrimSpace(cmnt + " (" + c.Cost() + ")")
+		case *cryptex.WeightedSSS:
+			cmnt = strings.TrimSpace(cmnt + " (" + c.Weighting() + ")")
+		case *cryptex.TimeLock:
+			cmnt = strings.TrimSpace(fmt.Sprintf("%s (%d squarings)", cmnt, c.Iterations))
+		case *cryptex.Derive:
+			cmnt = strings.TrimSpace(cmnt + " (" + strings.Join(c.Labels, ", ") + ")")
+		}
+	}
+
 	id, err := node.Digest()
 	if err != nil {
 		return "", err
@@ -131,10 +148,18 @@ func nodeTypeName(node *vcrypt.Node) (string, error) {
 		}
 
 		switch cptx.(type) {
+		case *cryptex.Age:
+			return "age", nil
 		case *cryptex.Box:
 			return "box", nil
 		case *cryptex.Demux:
 			return "demux", nil
+		case *cryptex.Derive:
+			return "derive", nil
+		case *cryptex.HybridKEM:
+			return "hybrid-kem", nil
+		case *cryptex.KDFSecretBox:
+			return "kdf-secretbox", nil
 		case *cryptex.Mux:
 			return "mux", nil
 		case *cryptex.OpenPGP:
@@ -143,8 +168,18 @@ func nodeTypeName(node *vcrypt.Node) (string, error) {
 			return "rsa", nil
 		case *cryptex.SecretBox:
 			return "secretbox", nil
+		case *cryptex.SSH:
+			return "ssh", nil
 		case *cryptex.SSS:
 			return "sss", nil
+		case *cryptex.Threshold:
+			return "threshold", nil
+		case *cryptex.TimeLock:
+			return "timelock", nil
+		case *cryptex.VSS:
+			return "vss", nil
+		case *cryptex.WeightedSSS:
+			return "weighted-sss", nil
 		case *cryptex.XOR:
 			return "xor", nil
 		default:
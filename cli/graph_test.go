@@ -200,6 +200,50 @@ func TestPlanGraph(t *testing.T) {
 				`*                                             0000000000000038 [material]   gloria material`,
 			},
 		},
+		{
+			config: `
+root = kdf
+
+[kdf-secretbox "kdf"]
+comment = passphrase key
+kdf = scrypt
+log-n = 10
+edge = passphrase
+edge = kdf material
+
+[password "passphrase"]
+
+[material "kdf material"]
+`,
+			lines: []string{
+				`*   0000000000000001 [kdf-secretbox] passphrase key (scrypt N=2^10 r=8 p=1)`,
+				`|\  `,
+				`| * 0000000000000002 [password]   passphrase`,
+				`*   0000000000000003 [material]   kdf material`,
+			},
+		},
+		{
+			config: `
+root = votes
+
+[weighted-sss "votes"]
+required-weight = 3
+edge = ciso:2
+edge = engineer 1
+edge = engineer 2
+
+[password "ciso"]
+[password "engineer 1"]
+[password "engineer 2"]
+`,
+			lines: []string{
+				`*-.   0000000000000001 [weighted-sss] votes (weight 3 of 2,1,1)`,
+				`|\ \  `,
+				`| | * 0000000000000002 [password]   ciso`,
+				`| *   0000000000000003 [password]   engineer 1`,
+				`*     0000000000000004 [password]   engineer 2`,
+			},
+		},
 	}
 
 	for _, test := range tests {
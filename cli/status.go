@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vcrypt/vcrypt"
+	"github.com/vcrypt/vcrypt/cli/graph"
+	"github.com/vcrypt/vcrypt/material"
+)
+
+// VaultStatus returns the textual representation of a Vault with the state of
+// each node, and the next steps towards unlocking the vault. Nodes are
+// displayed as 'S' if solved, 'R' if ready, '?' if missing a secret, or '*'
+// otherwise.
+func VaultStatus(vault *vcrypt.Vault, db material.DB) ([]string, []string, error) {
+	statuses, err := vault.Status(db)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nodes := make([]*graph.Node, 0, len(statuses))
+	for _, status := range statuses {
+		detail, err := nodeDetail(status.Node)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		marker, state := '*', status.State.String()
+		switch status.State {
+		case vcrypt.NodeSolved:
+			marker = 'S'
+		case vcrypt.NodeReady:
+			marker = 'R'
+		case vcrypt.NodeMissingSecret:
+			marker = '?'
+		case vcrypt.NodePending:
+			state = fmt.Sprintf("%d of %d", status.Solved, status.Required)
+		}
+
+		nodes = append(nodes, &graph.Node{
+			ID:     status.ID,
+			Edges:  status.Inputs,
+			Marker: marker,
+			Detail: fmt.Sprintf("%-14s %s", state, detail),
+		})
+	}
+
+	lines, err := graph.Lines(nodes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	steps, err := nextSteps(statuses)
+	if err != nil {
+		return nil, nil, err
+	}
+	return lines, steps, nil
+}
+
+// nextSteps walks down from the root through pending nodes & lists the
+// secrets to provide, or the materials to import, for each pending node.
+func nextSteps(statuses []*vcrypt.NodeStatus) ([]string, error) {
+	root := statuses[0]
+	if root.State == vcrypt.NodeSolved || root.State == vcrypt.NodeReady {
+		return []string{"unlock the vault"}, nil
+	}
+
+	byID := make(map[string]*vcrypt.NodeStatus, len(statuses))
+	for _, status := range statuses {
+		byID[string(status.ID)] = status
+	}
+
+	var (
+		steps   []string
+		queue   = []*vcrypt.NodeStatus{root}
+		visited = map[*vcrypt.NodeStatus]bool{root: true}
+	)
+	for len(queue) > 0 {
+		status := queue[0]
+		queue = queue[1:]
+
+		var secrets []string
+		for _, id := range status.Inputs {
+			input := byID[string(id)]
+			if visited[input] {
+				continue
+			}
+			visited[input] = true
+
+			desc, err := describe(input)
+			if err != nil {
+				return nil, err
+			}
+
+			switch {
+			case input.State == vcrypt.NodeReady:
+				steps = append(steps, "unlock to solve "+desc)
+			case input.State == vcrypt.NodeMissingSecret:
+				secrets = append(secrets, desc)
+			case input.State == vcrypt.NodePending && input.Type() == vcrypt.MarkerNode:
+				steps = append(steps, "import material for "+desc)
+			case input.State == vcrypt.NodePending:
+				queue = append(queue, input)
+			}
+		}
+
+		if len(secrets) == 0 {
+			continue
+		}
+
+		step := "provide " + strings.Join(secrets, ", ")
+		if status != root {
+			desc, err := describe(status)
+			if err != nil {
+				return nil, err
+			}
+			step += ", or import material for " + desc
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func describe(status *vcrypt.NodeStatus) (string, error) {
+	typ, err := nodeTypeName(status.Node)
+	if err != nil {
+		return "", err
+	}
+
+	cmnt, err := status.Comment()
+	if err != nil {
+		return "", err
+	}
+	if cmnt == "" {
+		return fmt.Sprintf("%s %x", typ, status.ID[:8]), nil
+	}
+	return fmt.Sprintf("%s '%s' (%x)", typ, cmnt, status.ID[:8]), nil
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/vcrypt/vcrypt"
+	"github.com/vcrypt/vcrypt/internal/test"
+)
+
+var vssPlanConfig = `
+root = quorum
+
+[vss "quorum"]
+max-shares = 3
+required-shares = 2
+edge = a box
+edge = b box
+edge = c box
+edge = commitments
+
+[secretbox "a box"]
+edge = a
+edge = a material
+
+[secretbox "b box"]
+edge = b
+edge = b material
+
+[secretbox "c box"]
+edge = c
+edge = c material
+
+[password "a"]
+[password "b"]
+[password "c"]
+
+[material "a material"]
+[material "b material"]
+[material "c material"]
+[material "commitments"]
+`
+
+func TestVaultStatus(t *testing.T) {
+	tests := []struct {
+		config  string
+		secrets map[string]string
+		solved  []string // comments of nodes with material in the db
+		steps   []string
+	}{
+		{
+			config:  string(test.TwoManPlanConfig),
+			secrets: map[string]string{"op 1 secret": "op 1", "op 2 secret": "op 2"},
+			steps: []string{
+				`provide password 'op 1 secret' (id), or import material for secretbox 'operator 1 key' (id)`,
+				`provide password 'op 2 secret' (id), or import material for secretbox 'operator 2 key' (id)`,
+			},
+		},
+		{
+			config:  string(test.TwoManPlanConfig),
+			secrets: map[string]string{"op 1 secret": "op 1", "op 2 secret": "op 2"},
+			solved:  []string{"operator 1 key"},
+			steps: []string{
+				`provide password 'op 2 secret' (id), or import material for secretbox 'operator 2 key' (id)`,
+			},
+		},
+		{
+			config:  string(test.TwoManPlanConfig),
+			secrets: map[string]string{"op 1 secret": "op 1", "op 2 secret": "op 2"},
+			solved:  []string{"operator 1 key", "operator 2 key"},
+			steps:   []string{"unlock the vault"},
+		},
+		{
+			config:  vssPlanConfig,
+			secrets: map[string]string{"a": "a", "b": "b", "c": "c"},
+			solved:  []string{"a box"},
+			steps: []string{
+				`provide password 'b' (id), or import material for secretbox 'b box' (id)`,
+				`provide password 'c' (id), or import material for secretbox 'c box' (id)`,
+			},
+		},
+		{
+			config:  vssPlanConfig,
+			secrets: map[string]string{"a": "a", "b": "b", "c": "c"},
+			solved:  []string{"a box", "c box"},
+			steps:   []string{"unlock the vault"},
+		},
+	}
+
+	for _, test := range tests {
+		vault, lockDB := lockVault(t, test.config, test.secrets)
+		db := solvedDB(t, vault, lockDB, test.solved)
+
+		_, steps, err := VaultStatus(vault, db)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		scrubIDs(steps)
+
+		want := strings.Join(test.steps, "\n\t")
+		got := strings.Join(steps, "\n\t")
+		if want != got {
+			t.Errorf("want steps:\n\t%s\ngot:\n\t%s", want, got)
+		}
+	}
+}
+
+func lockVault(t *testing.T, config string, secrets map[string]string) (*vcrypt.Vault, test.Driver) {
+	plan, err := vcrypt.BuildPlan(bytes.NewBufferString(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vault, err := vcrypt.NewVault(plan, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := test.Driver{}
+	for cmnt, data := range secrets {
+		db[cmnt] = []byte(data)
+	}
+	if err := vault.Lock(bytes.NewBufferString("secret"), db); err != nil {
+		t.Fatal(err)
+	}
+	return vault, db
+}
+
+// solvedDB copies the material of the nodes with the comments from the lock
+// db.
+func solvedDB(t *testing.T, vault *vcrypt.Vault, lockDB test.Driver, comments []string) test.Driver {
+	db := test.Driver{}
+	for _, node := range vault.Plan.Nodes {
+		cmnt, err := node.Comment()
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := node.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range comments {
+			if c == cmnt {
+				db[string(id)] = lockDB[string(id)]
+			}
+		}
+	}
+	return db
+}
+
+var idReg = regexp.MustCompile(`\([0-9a-f]{16}\)`)
+
+func scrubIDs(lines []string) {
+	for i, line := range lines {
+		lines[i] = idReg.ReplaceAllString(line, "(id)")
+	}
+}
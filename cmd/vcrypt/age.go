@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+
+	"github.com/vcrypt/vcrypt/secret"
+)
+
+// AgeKeyRing is an age identity file on the local filesystem, as written by
+// age-keygen.
+type AgeKeyRing struct {
+	identityFile string
+}
+
+// LoadIdentity loads the identities matching the secret's recipients. No data
+// is returned if the identity file or a matching identity is not found.
+func (r *AgeKeyRing) LoadIdentity(sec *secret.AgeIdentity) ([][]byte, error) {
+	path, err := expandPath(r.identityFile)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := sec.Load(f)
+	if err == secret.ErrNoAgeIdentity {
+		return nil, nil
+	}
+	return data, err
+}
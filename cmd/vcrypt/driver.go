@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 
-	"github.com/bgentry/speakeasy"
+	"github.com/vcrypt/vcrypt"
+	"github.com/vcrypt/vcrypt/cryptex"
 	"github.com/vcrypt/vcrypt/payload"
 	"github.com/vcrypt/vcrypt/secret"
 )
@@ -14,21 +18,47 @@ import (
 type Driver struct {
 	*DB
 	*OpenPGPKeyRing
+	*SSHKeyRing
+	*SSHAgent
+	*KeyDir
+	*AgeKeyRing
+	*PKCS11
 
-	pw io.WriteCloser
+	pw     io.WriteCloser
+	pr     io.ReadCloser
+	stream bool
+
+	provider *Provider
+}
+
+// Context returns a context for a slow cryptex operation that is canceled by
+// an interrupt. The interrupt is released by the returned stop func.
+func (d *Driver) Context() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// Progress displays the progress of a slow cryptex operation.
+func (d *Driver) Progress(cptx cryptex.Cryptex, done, total uint64) {
+	fmt.Fprintf(os.Stderr, "\ropening '%s': %3d%%", cptx.Comment(), done*100/total)
+	if done == total {
+		fmt.Fprintln(os.Stderr)
+	}
 }
 
-// LockPayload constructs a Payload from the Reader data. Attached & Detached
-// payloads are supported.
+// LockPayload constructs a Payload from the Reader data. Attached, Detached,
+// & Stream payloads are supported.
 func (d *Driver) LockPayload(r io.Reader) (payload.Payload, []byte, error) {
 	var (
 		pld payload.Payload
 		err error
 	)
 
-	if d.pw != nil {
+	switch {
+	case d.stream:
+		pld, err = payload.NewStream()
+	case d.pw != nil:
 		pld, err = payload.NewDetached()
-	} else {
+	default:
 		pld, err = payload.NewAttached()
 	}
 	if err != nil {
@@ -42,12 +72,46 @@ func (d *Driver) LockPayload(r io.Reader) (payload.Payload, []byte, error) {
 	return pld, key, nil
 }
 
-// LoadSecret returns the secret data for a given secret. Password & OpenPGPKey
-// secrets are supported.
+// PayloadWriter returns the Writer for payload ciphertext stored outside of
+// the vault.
+func (d *Driver) PayloadWriter() (io.Writer, error) {
+	if d.pw == nil {
+		return nil, errors.New("missing payload output file")
+	}
+	return d.pw, nil
+}
+
+// PayloadReader returns the Reader for payload ciphertext stored outside of
+// the vault.
+func (d *Driver) PayloadReader() (io.Reader, error) {
+	if d.pr == nil {
+		return nil, errors.New("missing payload input file")
+	}
+	return d.pr, nil
+}
+
+// LoadSecret returns the secret data for a given secret.
 func (d *Driver) LoadSecret(sec secret.Secret) ([][]byte, bool, error) {
+	return d.LoadNodeSecret(sec, vcrypt.NodePosition{})
+}
+
+// LoadNodeSecret returns the secret data for the secret held by the node at
+// the position. Password, OpenPGPKey, SSHKey, SSHAgent, X25519Key,
+// HybridKEMKey, AgeIdentity, & PKCS11Key secrets are supported. Passwords,
+// passphrases, & PINs are read from the secret provider by node comment or id
+// before falling back to a prompt showing the short node id.
+func (d *Driver) LoadNodeSecret(sec secret.Secret, pos vcrypt.NodePosition) ([][]byte, bool, error) {
+	ask := d.provider.Asker(sec.Comment(), pos.ID)
+	if len(pos.ID) >= 8 {
+		provAsk := ask
+		ask = func(prompt string) ([]byte, error) {
+			return provAsk(fmt.Sprintf("[%x] %s", pos.ID[:8], prompt))
+		}
+	}
+
 	switch sec := sec.(type) {
 	case *secret.Password:
-		passwd, err := speakeasy.FAsk(os.Stderr, fmt.Sprintf("password for '%s': ", sec.Comment()))
+		passwd, err := ask(fmt.Sprintf("password for '%s': ", sec.Comment()))
 		if err != nil {
 			return nil, false, err
 		}
@@ -55,9 +119,9 @@ func (d *Driver) LoadSecret(sec secret.Secret) ([][]byte, bool, error) {
 			return [][]byte{[]byte{}}, true, nil
 		}
 
-		return [][]byte{[]byte(passwd)}, false, nil
+		return [][]byte{passwd}, false, nil
 	case *secret.OpenPGPKey:
-		data, err := d.OpenPGPKeyRing.SerializePrivateKeys(sec.KeyIDs)
+		data, err := d.OpenPGPKeyRing.SerializePrivateKeys(sec.KeyIDs, ask)
 		if err != nil {
 			return nil, false, err
 		}
@@ -66,6 +130,66 @@ func (d *Driver) LoadSecret(sec secret.Secret) ([][]byte, bool, error) {
 		}
 
 		return [][]byte{data}, false, nil
+	case *secret.SSHKey:
+		data, err := d.SSHKeyRing.LoadPrivateKey(sec)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(data) == 0 {
+			return [][]byte{[]byte{}}, true, nil
+		}
+
+		return data, false, nil
+	case *secret.SSHAgent:
+		data, err := d.SSHAgent.SignChallenge(sec, pos.Lock)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(data) == 0 {
+			return [][]byte{[]byte{}}, true, nil
+		}
+
+		return data, false, nil
+	case *secret.X25519Key:
+		data, err := d.KeyDir.LoadX25519Key(sec, ask)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(data) == 0 {
+			return [][]byte{[]byte{}}, true, nil
+		}
+
+		return data, false, nil
+	case *secret.HybridKEMKey:
+		data, err := d.KeyDir.LoadHybridKEMKey(sec, ask)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(data) == 0 {
+			return [][]byte{[]byte{}}, true, nil
+		}
+
+		return data, false, nil
+	case *secret.AgeIdentity:
+		data, err := d.AgeKeyRing.LoadIdentity(sec)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(data) == 0 {
+			return [][]byte{[]byte{}}, true, nil
+		}
+
+		return data, false, nil
+	case *secret.PKCS11Key:
+		data, err := d.PKCS11.UnwrapKey(sec, ask, pos.Lock)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(data) == 0 {
+			return [][]byte{[]byte{}}, true, nil
+		}
+
+		return data, false, nil
 	default:
 		return nil, false, fmt.Errorf("unknown secret %#v\n", sec)
 	}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/vcrypt/vcrypt/secret"
+)
+
+// KeyDir is a directory of X25519 & hybrid KEM private key files written by
+// keygen.
+type KeyDir struct {
+	homedir string
+}
+
+// keyFileSecret is a secret loaded from a key file.
+type keyFileSecret interface {
+	Match(pub []byte) bool
+	Load(r io.Reader) ([][]byte, error)
+	LoadWithPassphrase(r io.Reader, passphrase []byte) ([][]byte, error)
+}
+
+// LoadX25519Key loads the private key data for the key file matching the
+// secret's public key.
+func (d *KeyDir) LoadX25519Key(sec *secret.X25519Key, ask func(prompt string) ([]byte, error)) ([][]byte, error) {
+	return d.load(sec, secret.ParseX25519PublicKey, ask)
+}
+
+// LoadHybridKEMKey loads the private key data for the key file matching the
+// secret's public key.
+func (d *KeyDir) LoadHybridKEMKey(sec *secret.HybridKEMKey, ask func(prompt string) ([]byte, error)) ([][]byte, error) {
+	return d.load(sec, secret.ParseHybridKEMPublicKey, ask)
+}
+
+// load finds the key file with a public key matching the secret. Passphrase
+// protected keys are first decrypted with a passphrase from ask. No data is
+// returned if a matching key is not found.
+func (d *KeyDir) load(sec keyFileSecret, parsePublicKey func([]byte) ([]byte, error), ask func(prompt string) ([]byte, error)) ([][]byte, error) {
+	pattern, err := expandPath(d.homedir, "*.key")
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		pub, err := parsePublicKey(data)
+		if err != nil || !sec.Match(pub) {
+			continue
+		}
+
+		keys, err := sec.Load(bytes.NewReader(data))
+		if err != secret.ErrKeyFileEncrypted {
+			return keys, err
+		}
+
+		prompt := fmt.Sprintf("passphrase for key %q: ", path)
+		pass, err := ask(prompt)
+		if err != nil {
+			return nil, err
+		}
+
+		return sec.LoadWithPassphrase(bytes.NewReader(data), pass)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bgentry/speakeasy"
+	"github.com/vcrypt/vcrypt/cryptex"
+	"github.com/vcrypt/vcrypt/secret"
+	"golang.org/x/crypto/nacl/box"
+)
+
+var (
+	keygenFS = flag.NewFlagSet("keygen", flag.ExitOnError)
+
+	keygenVars = struct {
+		out, comment, keyDir *string
+	}{
+		out:     keygenFS.String("out", "", "private key file - default key.dir/<key id>.key"),
+		comment: keygenFS.String("comment", "", "key comment"),
+		keyDir:  keygenFS.String("key.dir", "~/.vcrypt/keys", "box & hybrid-kem key directory"),
+	}
+)
+
+func keygen(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "usage: vcrypt keygen box|hybrid-kem [<args>]")
+		os.Exit(1)
+	}
+	typ := args[0]
+	keygenFS.Parse(args[1:])
+
+	var (
+		out    = *keygenVars.out
+		cmnt   = *keygenVars.comment
+		keyDir = *keygenVars.keyDir
+
+		priv, pub []byte
+		secType   string
+		marshal   func(key, passphrase []byte) ([]byte, error)
+	)
+
+	switch typ {
+	case "box":
+		pk, sk, err := box.GenerateKey(rand.Reader)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		priv, pub = sk[:], pk[:]
+		secType, marshal = "x25519-key", secret.MarshalX25519PrivateKey
+	case "hybrid-kem":
+		sk, pk, err := cryptex.GenerateHybridKEMKey(rand.Reader)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		priv, pub = sk, pk
+		secType, marshal = "hybrid-kem-key", secret.MarshalHybridKEMPrivateKey
+	default:
+		fmt.Fprintf(os.Stderr, "unknown key type %q, must be box or hybrid-kem\n", typ)
+		os.Exit(1)
+	}
+
+	pass, err := askNewPassphrase()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	data, err := marshal(priv, []byte(pass))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	digest := sha256.Sum256(pub)
+	keyID := hex.EncodeToString(digest[:8])
+
+	if out == "" {
+		if out, err = expandPath(keyDir, keyID+".key"); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if err := os.MkdirAll(filepath.Dir(out), 0700); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if _, err := f.Write(data); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if err := f.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "wrote private key to %s\n", out)
+
+	name := cmnt
+	if name == "" {
+		name = keyID
+	}
+	pk := base64.StdEncoding.EncodeToString(pub)
+
+	// the ciphertext material is the first input, the private key the second
+	fmt.Printf("[%s %q]\npublickey = %s\nedge = %s material\nedge = %s key\n\n", typ, name, pk, name, name)
+	fmt.Printf("[material \"%s material\"]\n\n", name)
+	fmt.Printf("[%s \"%s key\"]\npublickey = %s\n", secType, name, pk)
+}
+
+func askNewPassphrase() (string, error) {
+	pass, err := speakeasy.FAsk(os.Stderr, "passphrase (empty for no passphrase): ")
+	if err != nil {
+		return "", err
+	}
+	if pass == "" {
+		return "", nil
+	}
+
+	confirm, err := speakeasy.FAsk(os.Stderr, "confirm passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if pass != confirm {
+		return "", errors.New("passphrases do not match")
+	}
+	return pass, nil
+}
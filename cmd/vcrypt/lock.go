@@ -14,17 +14,29 @@ var (
 	lockFS = flag.NewFlagSet("lock", flag.ExitOnError)
 
 	lockVars = struct {
-		in, out, plan, comment, detach *string
+		in, out, plan, comment, detach, stream, requireSeal, sealPolicy *string
 
-		dbDir *string
+		dbDir, pkcs11Module *string
+
+		secretConfig *string
+		secrets      *Provider
 	}{
 		in:      lockFS.String("in", "", "input file - default stdin"),
 		out:     lockFS.String("out", "", "output file - default stdout"),
 		plan:    lockFS.String("plan", "", "plan file"),
 		comment: lockFS.String("comment", "", "vault comment"),
 		detach:  lockFS.String("detach", "", "detached payload file"),
+		stream:  lockFS.String("stream", "", "streamed payload file"),
+
+		requireSeal: lockFS.String("require-seal", "", "require a plan seal by OpenPGP or SSH fingerprint"),
+		sealPolicy:  lockFS.String("seal-policy", "", "seal policy file of trusted plan signers"),
 
 		dbDir: lockFS.String("db.dir", "~/.vcrypt/db", "vcrypt database directory"),
+
+		pkcs11Module: lockFS.String("pkcs11.module", "", "PKCS#11 module for pkcs11-key tokens, e.g. libsofthsm2.so"),
+
+		secretConfig: lockFS.String("secret.config", "", "secret provider config file"),
+		secrets:      providerFlag(lockFS),
 	}
 )
 
@@ -41,8 +53,16 @@ func lock(args []string) {
 		pfile = *lockVars.plan
 		cmnt  = *lockVars.comment
 		dfile = *lockVars.detach
+		sfile = *lockVars.stream
+		rseal = *lockVars.requireSeal
+		spol  = *lockVars.sealPolicy
 
 		dbDir = *lockVars.dbDir
+
+		pkcs11Module = *lockVars.pkcs11Module
+
+		secretConfig = *lockVars.secretConfig
+		provider     = lockVars.secrets
 	)
 
 	if pfile == "" {
@@ -50,6 +70,11 @@ func lock(args []string) {
 		os.Exit(1)
 	}
 
+	if dfile != "" && sfile != "" {
+		fmt.Fprintln(os.Stderr, "conflicting arguments: -detach & -stream")
+		os.Exit(1)
+	}
+
 	if in == "" {
 		r = os.Stdin
 	} else {
@@ -90,17 +115,42 @@ func lock(args []string) {
 		os.Exit(1)
 	}
 
+	pols, err := sealPolicies(rseal, spol)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	for _, pol := range pols {
+		if err := plan.CheckSealPolicy(pol); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
 	vault, err := vcrypt.NewVault(plan, cmnt)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
 
+	if err := provider.LoadConfig(secretConfig); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
 	drv := &Driver{
 		DB: &DB{
 			vault:   vault,
 			baseDir: dbDir,
 		},
+		SSHAgent: &SSHAgent{
+			sock: os.Getenv("SSH_AUTH_SOCK"),
+		},
+		PKCS11: &PKCS11{
+			module: pkcs11Module,
+		},
+
+		provider: provider,
 	}
 
 	if dfile != "" {
@@ -110,11 +160,26 @@ func lock(args []string) {
 		}
 	}
 
+	if sfile != "" {
+		if drv.pw, err = os.Create(sfile); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		drv.stream = true
+	}
+
 	if err := vault.Lock(r, drv); err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
 
+	if drv.pw != nil {
+		if err := drv.pw.Close(); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
 	if data, err = vcrypt.Armor(vault); err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
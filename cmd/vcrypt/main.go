@@ -22,10 +22,22 @@ func main() {
 		importM(args)
 	case "inspect":
 		inspect(args)
+	case "keygen":
+		keygen(args)
 	case "lock":
 		lock(args)
+	case "rekey":
+		rekey(args)
+	case "reshare":
+		reshare(args)
+	case "seal":
+		sealM(args)
+	case "status":
+		status(args)
 	case "unlock":
 		unlock(args)
+	case "verify":
+		verify(args)
 	default:
 		help()
 		os.Exit(1)
@@ -41,8 +53,14 @@ func help() {
 		"	export  Export material data",
 		"	import  Import material data",
 		"	inspect Show vault, plan, & material info",
+		"	keygen	Generate a box or hybrid-kem key pair",
 		"	lock	Encrypt data to a vault",
+		"	rekey	Move a vault to a new plan",
+		"	reshare	Replace a vault node with new shares",
+		"	seal	Sign a plan or vault",
+		"	status	Show what can be solved next for a vault",
 		"	unlock	Decrypt data from a vault",
+		"	verify	Check plan or vault seals",
 	}
 
 	fmt.Println(strings.Join(help, "\n"))
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/bgentry/speakeasy"
+	"github.com/vcrypt/vcrypt"
+	"github.com/vcrypt/vcrypt/seal"
 	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/packet"
 )
 
 // OpenPGPKeyRing is a OpenPGP keyring located on the local filesystem.
@@ -17,9 +19,9 @@ type OpenPGPKeyRing struct {
 }
 
 // SerializePrivateKeys writes the private key data of the keyring keys
-// identified by ids. Encrypted keys are first decrypted with a passphrase and
-// the decrypted data is returned.
-func (r *OpenPGPKeyRing) SerializePrivateKeys(ids []uint64) ([]byte, error) {
+// identified by ids. Encrypted keys are first decrypted with a passphrase from
+// ask and the decrypted data is returned.
+func (r *OpenPGPKeyRing) SerializePrivateKeys(ids []uint64, ask func(prompt string) ([]byte, error)) ([]byte, error) {
 	ents := make(openpgp.EntityList, 0)
 	for _, id := range ids {
 		ks, err := r.privateKey(id)
@@ -38,26 +40,14 @@ func (r *OpenPGPKeyRing) SerializePrivateKeys(ids []uint64) ([]byte, error) {
 				fmt.Fprintf(os.Stderr, "user: %q\n", user)
 			}
 
-			prompt := fmt.Sprintf("passphrase for OpenPGP key %q: ", key.PublicKey.KeyIdString())
-			pass, err := speakeasy.FAsk(os.Stderr, prompt)
-			if err != nil {
-				return nil, err
-			}
-
-			if err := key.PrivateKey.Decrypt([]byte(pass)); err != nil {
+			if err := decryptKey(key.PrivateKey, ask); err != nil {
 				return nil, err
 			}
 		}
 
 		for _, subkey := range key.Entity.Subkeys {
 			if subkey.PrivateKey.Encrypted {
-				prompt := fmt.Sprintf("passphrase for OpenPGP key %q: ", subkey.PublicKey.KeyIdString())
-				pass, err := speakeasy.FAsk(os.Stderr, prompt)
-				if err != nil {
-					return nil, err
-				}
-
-				if err := subkey.PrivateKey.Decrypt([]byte(pass)); err != nil {
+				if err := decryptKey(subkey.PrivateKey, ask); err != nil {
 					return nil, err
 				}
 			}
@@ -74,6 +64,54 @@ func (r *OpenPGPKeyRing) SerializePrivateKeys(ids []uint64) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// Sealer returns a Sealer for the keyring entity identified by id. The
+// entity's signing keys are decrypted with a passphrase if encrypted.
+func (r *OpenPGPKeyRing) Sealer(id uint64) (vcrypt.Sealer, error) {
+	ks, err := r.privateKey(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(ks) == 0 {
+		return nil, fmt.Errorf("OpenPGP key %X not found", id)
+	}
+
+	ent := ks[0].Entity
+	if ent.PrivateKey != nil && ent.PrivateKey.Encrypted {
+		if err := decryptKey(ent.PrivateKey, askTerminal); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, subkey := range ent.Subkeys {
+		if subkey.PrivateKey != nil && subkey.PrivateKey.Encrypted && subkey.Sig.FlagSign {
+			if err := decryptKey(subkey.PrivateKey, askTerminal); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return openPGPSealer{ent}, nil
+}
+
+type openPGPSealer struct {
+	*openpgp.Entity
+}
+
+// Seal constructs an OpenPGP seal for data signed by the entity.
+func (s openPGPSealer) Seal(data []byte) (seal.Seal, error) {
+	return seal.NewOpenPGP(s.Entity, data)
+}
+
+func decryptKey(key *packet.PrivateKey, ask func(prompt string) ([]byte, error)) error {
+	prompt := fmt.Sprintf("passphrase for OpenPGP key %q: ", key.PublicKey.KeyIdString())
+	pass, err := ask(prompt)
+	if err != nil {
+		return err
+	}
+
+	return key.Decrypt(pass)
+}
+
 func (r *OpenPGPKeyRing) privateKey(id uint64) ([]openpgp.Key, error) {
 	if r.secring == nil {
 		path, err := expandPath(r.homedir, "secring.gpg")
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/vcrypt/vcrypt/secret"
+)
+
+// pkcs11Token is an open PKCS#11 token session.
+type pkcs11Token interface {
+	secret.PKCS11Token
+
+	Close() error
+}
+
+// PKCS11 is a PKCS#11 module, such as SoftHSMv2's libsofthsm2.so, for the
+// tokens holding pkcs11-key private keys.
+type PKCS11 struct {
+	module string
+
+	pins map[string]string
+}
+
+// UnwrapKey loads the secret data from the token's unwrap of the secret's
+// wrapped key. The token PIN is asked for once per token. No data is returned
+// if no module is configured or the PIN is blank, unless the vault is being
+// locked, in which case an error is returned.
+func (m *PKCS11) UnwrapKey(sec *secret.PKCS11Key, ask func(prompt string) ([]byte, error), lock bool) ([][]byte, error) {
+	if m.module == "" {
+		if lock {
+			return nil, errors.New("pkcs11-key secret requires -pkcs11.module")
+		}
+		return nil, nil
+	}
+
+	path, err := expandPath(m.module)
+	if err != nil {
+		return nil, err
+	}
+
+	name := sec.Token
+	if name == "" {
+		name = fmt.Sprintf("slot %d", sec.Slot)
+	}
+
+	pin, ok := m.pins[name]
+	if !ok {
+		data, err := ask(fmt.Sprintf("PIN for token '%s': ", name))
+		if err != nil {
+			return nil, err
+		}
+		if pin = string(data); len(pin) == 0 {
+			if lock {
+				return nil, fmt.Errorf("pkcs11-key secret requires a PIN for token '%s'", name)
+			}
+			return nil, nil
+		}
+
+		if m.pins == nil {
+			m.pins = map[string]string{}
+		}
+		m.pins[name] = pin
+	}
+
+	tok, err := openPKCS11Token(path, sec.Token, sec.Slot, pin)
+	if err != nil {
+		return nil, err
+	}
+	defer tok.Close()
+
+	data, err := sec.Unwrap(tok)
+	if err != nil {
+		return nil, err
+	}
+	return sec.Load(bytes.NewReader(data))
+}
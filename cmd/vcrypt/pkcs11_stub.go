@@ -0,0 +1,10 @@
+//go:build !pkcs11
+// +build !pkcs11
+
+package main
+
+import "errors"
+
+func openPKCS11Token(module, token string, slot uint64, pin string) (pkcs11Token, error) {
+	return nil, errors.New("pkcs11-key secrets require vcrypt built with -tags pkcs11")
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"testing"
+
+	"github.com/vcrypt/vcrypt/secret"
+)
+
+func TestPKCS11UnwrapKeyRequired(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkix, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sec, err := secret.NewPKCS11Key("vault", 0, "root", pkix, "hsm key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blank := func(string) ([]byte, error) { return nil, nil }
+
+	// no module
+	if data, err := (&PKCS11{}).UnwrapKey(sec, blank, false); err != nil || data != nil {
+		t.Errorf("want skip without module at unlock, got %x, %v", data, err)
+	}
+	if _, err := (&PKCS11{}).UnwrapKey(sec, blank, true); err == nil {
+		t.Errorf("want lock error without module, got nil")
+	}
+
+	// blank PIN
+	m := &PKCS11{module: "libsofthsm2.so"}
+	if data, err := m.UnwrapKey(sec, blank, false); err != nil || data != nil {
+		t.Errorf("want skip for blank PIN at unlock, got %x, %v", data, err)
+	}
+	if _, err := m.UnwrapKey(sec, blank, true); err == nil {
+		t.Errorf("want lock error for blank PIN, got nil")
+	}
+}
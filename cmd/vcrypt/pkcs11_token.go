@@ -0,0 +1,149 @@
+//go:build pkcs11
+// +build pkcs11
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/miekg/pkcs11"
+)
+
+// session is a logged in session on a PKCS#11 token.
+type session struct {
+	ctx *pkcs11.Ctx
+	sh  pkcs11.SessionHandle
+}
+
+func openPKCS11Token(module, token string, slot uint64, pin string) (pkcs11Token, error) {
+	ctx := pkcs11.New(module)
+	if ctx == nil {
+		return nil, fmt.Errorf("could not load PKCS#11 module %q", module)
+	}
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, err
+	}
+
+	s := &session{ctx: ctx}
+	if err := s.open(token, uint(slot), pin); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *session) open(token string, slot uint, pin string) error {
+	slots, err := s.ctx.GetSlotList(true)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, id := range slots {
+		if token == "" {
+			found = id == slot
+		} else {
+			info, err := s.ctx.GetTokenInfo(id)
+			if err != nil {
+				return err
+			}
+			found = info.Label == token
+		}
+
+		if found {
+			slot = id
+			break
+		}
+	}
+	if !found {
+		return errors.New("PKCS#11 token not found")
+	}
+
+	if s.sh, err = s.ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION); err != nil {
+		return err
+	}
+	return s.ctx.Login(s.sh, pkcs11.CKU_USER, pin)
+}
+
+// DecryptOAEP decrypts the ciphertext with the labeled RSA private key on the
+// token.
+func (s *session) DecryptOAEP(label string, ciphertext []byte) ([]byte, error) {
+	key, err := s.privateKey(label)
+	if err != nil {
+		return nil, err
+	}
+
+	params := pkcs11.NewOAEPParams(pkcs11.CKM_SHA256, pkcs11.CKG_MGF1_SHA256, pkcs11.CKZ_DATA_SPECIFIED, nil)
+	mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS_OAEP, params)}
+	if err := s.ctx.DecryptInit(s.sh, mech, key); err != nil {
+		return nil, err
+	}
+	return s.ctx.Decrypt(s.sh, ciphertext)
+}
+
+// ECDH derives the shared secret of the labeled EC private key on the token &
+// the peer point.
+func (s *session) ECDH(label string, peer []byte) ([]byte, error) {
+	key, err := s.privateKey(label)
+	if err != nil {
+		return nil, err
+	}
+
+	params := pkcs11.NewECDH1DeriveParams(pkcs11.CKD_NULL, nil, peer)
+	mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDH1_DERIVE, params)}
+	tmpl := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_GENERIC_SECRET),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, 32),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, true),
+	}
+
+	shared, err := s.ctx.DeriveKey(s.sh, mech, key, tmpl)
+	if err != nil {
+		return nil, err
+	}
+	defer s.ctx.DestroyObject(s.sh, shared)
+
+	attrs, err := s.ctx.GetAttributeValue(s.sh, shared, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attrs[0].Value, nil
+}
+
+// Close logs out & releases the module.
+func (s *session) Close() error {
+	if s.sh != 0 {
+		s.ctx.Logout(s.sh)
+		s.ctx.CloseSession(s.sh)
+	}
+	s.ctx.Finalize()
+	s.ctx.Destroy()
+	return nil
+}
+
+func (s *session) privateKey(label string) (pkcs11.ObjectHandle, error) {
+	tmpl := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := s.ctx.FindObjectsInit(s.sh, tmpl); err != nil {
+		return 0, err
+	}
+	defer s.ctx.FindObjectsFinal(s.sh)
+
+	objs, _, err := s.ctx.FindObjects(s.sh, 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(objs) == 0 {
+		return 0, fmt.Errorf("no private key labeled %q on PKCS#11 token", label)
+	}
+	return objs[0], nil
+}
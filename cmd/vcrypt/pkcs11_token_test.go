@@ -0,0 +1,192 @@
+//go:build pkcs11
+// +build pkcs11
+
+package main
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+	"github.com/vcrypt/vcrypt/secret"
+)
+
+// oidP256 is the DER encoded prime256v1 curve OID.
+var oidP256 = []byte{0x06, 0x08, 0x2a, 0x86, 0x48, 0xce, 0x3d, 0x03, 0x01, 0x07}
+
+// TestPKCS11Token unwraps pkcs11-key secrets on a SoftHSMv2 token. It is
+// skipped unless SOFTHSM2_MODULE is the path to libsofthsm2.so. The token
+// label & PIN default to the README's "vault" & "1234", and may be set with
+// SOFTHSM2_TOKEN & SOFTHSM2_PIN.
+func TestPKCS11Token(t *testing.T) {
+	module := os.Getenv("SOFTHSM2_MODULE")
+	if module == "" {
+		t.Skip("SOFTHSM2_MODULE not set")
+	}
+	token := envDefault("SOFTHSM2_TOKEN", "vault")
+	pin := envDefault("SOFTHSM2_PIN", "1234")
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, keyType := range []string{"rsa", "ec"} {
+		label := "vcrypt test " + keyType + " " + hex.EncodeToString(suffix)
+
+		pkix := generateTokenKey(t, module, token, pin, keyType, label)
+		defer destroyTokenKey(t, module, token, pin, label)
+
+		sec, err := secret.NewPKCS11Key(token, 0, label, pkix, "test PKCS11Key secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var keys [][]byte
+		for i := 0; i < 2; i++ {
+			m := &PKCS11{module: module}
+			data, err := m.UnwrapKey(sec, func(string) ([]byte, error) { return []byte(pin), nil }, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(data) != 1 || len(data[0]) != 32 {
+				t.Fatalf("want a 32 byte %s key, got %x", keyType, data)
+			}
+			keys = append(keys, data[0])
+		}
+		if !bytes.Equal(keys[0], keys[1]) {
+			t.Errorf("want equal %s keys, got %x & %x", keyType, keys[0], keys[1])
+		}
+
+		sec.Label = "missing " + label
+		m := &PKCS11{module: module}
+		if _, err := m.UnwrapKey(sec, func(string) ([]byte, error) { return []byte(pin), nil }, false); err == nil {
+			t.Errorf("want unwrap error for missing %s key, got nil", keyType)
+		}
+	}
+}
+
+// generateTokenKey generates a labeled RSA or P-256 key pair on the token &
+// returns the PKIX encoded public key.
+func generateTokenKey(t *testing.T, module, token, pin, keyType, label string) []byte {
+	s := openTestSession(t, module, token, pin)
+	defer s.Close()
+
+	var (
+		mech     []*pkcs11.Mechanism
+		pubTmpl  []*pkcs11.Attribute
+		privTmpl = []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		}
+		pubAttrs []*pkcs11.Attribute
+	)
+	switch keyType {
+	case "rsa":
+		mech = []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS_KEY_PAIR_GEN, nil)}
+		pubTmpl = []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+			pkcs11.NewAttribute(pkcs11.CKA_MODULUS_BITS, 2048),
+			pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, []byte{1, 0, 1}),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		}
+		privTmpl = append(privTmpl, pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true))
+		pubAttrs = []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_MODULUS, nil),
+			pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, nil),
+		}
+	case "ec":
+		mech = []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)}
+		pubTmpl = []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, oidP256),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		}
+		privTmpl = append(privTmpl, pkcs11.NewAttribute(pkcs11.CKA_DERIVE, true))
+		pubAttrs = []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+		}
+	}
+
+	pubKey, _, err := s.ctx.GenerateKeyPair(s.sh, mech, pubTmpl, privTmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attrs, err := s.ctx.GetAttributeValue(s.sh, pubKey, pubAttrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pub interface{}
+	switch keyType {
+	case "rsa":
+		pub = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(attrs[0].Value),
+			E: int(new(big.Int).SetBytes(attrs[1].Value).Int64()),
+		}
+	case "ec":
+		// CKA_EC_POINT is a DER octet string of the uncompressed point
+		var point []byte
+		if _, err := asn1.Unmarshal(attrs[0].Value, &point); err != nil {
+			t.Fatal(err)
+		}
+		if pub, err = ecdh.P256().NewPublicKey(point); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pkix, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkix
+}
+
+// destroyTokenKey removes the labeled key pair from the token.
+func destroyTokenKey(t *testing.T, module, token, pin, label string) {
+	s := openTestSession(t, module, token, pin)
+	defer s.Close()
+
+	tmpl := []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_LABEL, label)}
+	if err := s.ctx.FindObjectsInit(s.sh, tmpl); err != nil {
+		t.Fatal(err)
+	}
+	objs, _, err := s.ctx.FindObjects(s.sh, 2)
+	s.ctx.FindObjectsFinal(s.sh)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, obj := range objs {
+		if err := s.ctx.DestroyObject(s.sh, obj); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func openTestSession(t *testing.T, module, token, pin string) *session {
+	tok, err := openPKCS11Token(module, token, 0, pin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok.(*session)
+}
+
+func envDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+
+	"github.com/vcrypt/vcrypt/config"
+	"github.com/vcrypt/vcrypt/seal"
+)
+
+// loadSealPolicy reads a seal policy file in the plan config format:
+//
+//	threshold = 2
+//
+//	[signer "alice"]
+//	fingerprint = 0D6B 3F8E 2C71 94A6 E05F  9C3A 5A8D 2B2A 1CE9 D7B4
+//
+//	[signer "bob"]
+//	authorized-key = ssh-ed25519 AAAA... bob@example.com
+func loadSealPolicy(path string) (*seal.Policy, error) {
+	path, err := expandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var pol config.Policy
+	if err := config.NewDecoder(f).Decode(&pol); err != nil {
+		return nil, err
+	}
+
+	return pol.Policy()
+}
+
+// requireSeal is a policy satisfied by a seal from the key identified by
+// keyID: a full hex OpenPGP fingerprint, or an SSH SHA256 fingerprint.
+func requireSeal(keyID string) (*seal.Policy, error) {
+	id, err := seal.ParseIdentity(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &seal.Policy{
+		Threshold: 1,
+		Signers: []seal.Signer{
+			{Name: keyID, IDs: []string{id}},
+		},
+	}, nil
+}
+
+// sealPolicies returns the policies from the -require-seal & -seal-policy
+// flags.
+func sealPolicies(keyID, path string) ([]*seal.Policy, error) {
+	var pols []*seal.Policy
+	if keyID != "" {
+		pol, err := requireSeal(keyID)
+		if err != nil {
+			return nil, err
+		}
+		pols = append(pols, pol)
+	}
+	if path != "" {
+		pol, err := loadSealPolicy(path)
+		if err != nil {
+			return nil, err
+		}
+		pols = append(pols, pol)
+	}
+	return pols, nil
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bgentry/speakeasy"
+	"github.com/vcrypt/vcrypt/config"
+)
+
+// Provider supplies passwords, passphrases, & PINs without prompting. Sources
+// are keyed by node comment or node ID prefix, as shown by inspect, and are
+// one of:
+//
+//	file:<path>     contents of the file
+//	fd:<n>          contents read from the open file descriptor
+//	env:<name>      value of the environment variable
+//	cmd:<command>   output of the shell command, e.g. a pinentry wrapper
+//
+// A single trailing newline is removed from the data.
+type Provider struct {
+	sources map[string]string
+	cache   map[string][]byte
+}
+
+// providerConfig is the provider config file format:
+//
+//	[secret "alice password"]
+//	env = ALICE_PASSWORD
+//
+//	[secret "3f2a9c01"]
+//	command = pass show vcrypt/bob
+type providerConfig struct {
+	Secrets map[string]providerSource `vcrypt:"secret,section"`
+}
+
+type providerSource struct {
+	File    string `vcrypt:"file,optional"`
+	FD      string `vcrypt:"fd,optional"`
+	Env     string `vcrypt:"env,optional"`
+	Command string `vcrypt:"command,optional"`
+}
+
+// String is the flag.Value String method.
+func (p *Provider) String() string {
+	names := make([]string, 0, len(p.sources))
+	for name := range p.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// Set adds a source from a name=source flag value.
+func (p *Provider) Set(value string) error {
+	idx := strings.Index(value, "=")
+	if idx < 1 {
+		return errors.New("secret source must be name=source")
+	}
+	return p.add(value[:idx], value[idx+1:])
+}
+
+// LoadConfig adds the sources in the provider config file at path.
+func (p *Provider) LoadConfig(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	path, err := expandPath(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var cfg providerConfig
+	if err := config.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+
+	for name, src := range cfg.Secrets {
+		var srcs []string
+		if src.File != "" {
+			srcs = append(srcs, "file:"+src.File)
+		}
+		if src.FD != "" {
+			srcs = append(srcs, "fd:"+src.FD)
+		}
+		if src.Env != "" {
+			srcs = append(srcs, "env:"+src.Env)
+		}
+		if src.Command != "" {
+			srcs = append(srcs, "cmd:"+src.Command)
+		}
+		if len(srcs) != 1 {
+			return fmt.Errorf("secret %q requires one of file, fd, env, or command", name)
+		}
+
+		if err := p.add(name, srcs[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Provider) add(name, src string) error {
+	parts := strings.SplitN(src, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return fmt.Errorf("secret source for %q must be kind:arg", name)
+	}
+
+	switch kind, arg := parts[0], parts[1]; kind {
+	case "file", "env", "cmd":
+	case "fd":
+		if fd, err := strconv.Atoi(arg); err != nil || fd < 0 {
+			return fmt.Errorf("invalid file descriptor %q for %q", arg, name)
+		}
+	default:
+		return fmt.Errorf("unknown secret source %q for %q", kind, name)
+	}
+
+	if p.sources == nil {
+		p.sources = map[string]string{}
+	}
+	if _, ok := p.sources[name]; ok {
+		return fmt.Errorf("duplicate secret source for %q", name)
+	}
+	p.sources[name] = src
+	return nil
+}
+
+// Lookup returns the data for the node with the comment & ID. A source for
+// the comment takes precedence over one for an ID prefix. No data is returned
+// if there is no matching source.
+func (p *Provider) Lookup(comment string, id []byte) ([]byte, bool, error) {
+	name, ok := comment, false
+	if _, ok = p.sources[comment]; !ok && id != nil {
+		hexID := hex.EncodeToString(id)
+		for src := range p.sources {
+			if len(src) >= 8 && strings.HasPrefix(hexID, strings.ToLower(src)) {
+				if ok {
+					return nil, false, fmt.Errorf("ambiguous secret source for node %s", hexID)
+				}
+				name, ok = src, true
+			}
+		}
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	if data, ok := p.cache[name]; ok {
+		return data, true, nil
+	}
+
+	data, err := p.read(p.sources[name])
+	if err != nil {
+		return nil, false, fmt.Errorf("secret source for %q: %s", name, err)
+	}
+
+	if p.cache == nil {
+		p.cache = map[string][]byte{}
+	}
+	p.cache[name] = data
+	return data, true, nil
+}
+
+// Asker returns a function for the data of the node from a source, falling
+// back to the terminal prompt.
+func (p *Provider) Asker(comment string, id []byte) func(prompt string) ([]byte, error) {
+	return func(prompt string) ([]byte, error) {
+		if p != nil {
+			data, ok, err := p.Lookup(comment, id)
+			if err != nil || ok {
+				return data, err
+			}
+		}
+		return askTerminal(prompt)
+	}
+}
+
+// askTerminal prompts for a password, passphrase, or PIN without echo.
+func askTerminal(prompt string) ([]byte, error) {
+	pass, err := speakeasy.FAsk(os.Stderr, prompt)
+	return []byte(pass), err
+}
+
+func (p *Provider) read(src string) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	parts := strings.SplitN(src, ":", 2)
+	switch kind, arg := parts[0], parts[1]; kind {
+	case "file":
+		var path string
+		if path, err = expandPath(arg); err == nil {
+			data, err = ioutil.ReadFile(path)
+		}
+	case "fd":
+		fd, perr := strconv.Atoi(arg)
+		if perr != nil || fd < 0 {
+			return nil, fmt.Errorf("invalid file descriptor %q", arg)
+		}
+		f := os.NewFile(uintptr(fd), "fd"+arg)
+		defer f.Close()
+		data, err = ioutil.ReadAll(f)
+	case "env":
+		val, ok := os.LookupEnv(arg)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s is not set", arg)
+		}
+		data = []byte(val)
+	case "cmd":
+		cmd := exec.Command("/bin/sh", "-c", arg)
+		cmd.Stdin, cmd.Stderr = os.Stdin, os.Stderr
+		data, err = cmd.Output()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	return bytes.TrimSuffix(data, []byte("\r")), nil
+}
+
+// providerFlag defines a repeated -secret name=source flag on the flag set.
+func providerFlag(fs *flag.FlagSet) *Provider {
+	p := &Provider{}
+	fs.Var(p, "secret", "password, passphrase, or PIN source for a node comment or id as name=file:path, fd:n, env:var, or cmd:command - repeatable")
+	return p
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestProviderSet(t *testing.T) {
+	tests := []struct {
+		value string
+		err   bool
+	}{
+		{"alice=env:ALICE_PASSWORD", false},
+		{"alice=fd:0", false},
+		{"alice=cmd:pass show alice", false},
+		{"alice=env", true},
+		{"alice=file", true},
+		{"alice=file:", true},
+		{"alice=fd:-1", true},
+		{"alice=fd:stdin", true},
+		{"alice=url:https://example.com", true},
+		{"=env:ALICE_PASSWORD", true},
+	}
+
+	for _, test := range tests {
+		p := &Provider{}
+		if err := p.Set(test.value); (err != nil) != test.err {
+			t.Errorf("%q: want error %t, got %v", test.value, test.err, err)
+		}
+	}
+}
+
+func TestProviderLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets")
+	data := []byte(`
+[secret "alice password"]
+fd = 0
+
+[secret "bob password"]
+env = BOB_PASSWORD
+`)
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Provider{}
+	if err := p.LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "fd:0", p.sources["alice password"]; want != got {
+		t.Errorf("want source %q, got %q", want, got)
+	}
+
+	t.Setenv("BOB_PASSWORD", "bob secret\n")
+	if data, ok, err := p.Lookup("bob password", nil); err != nil || !ok || string(data) != "bob secret" {
+		t.Errorf("want bob secret, got %q, %t, %v", data, ok, err)
+	}
+}
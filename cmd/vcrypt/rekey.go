@@ -0,0 +1,198 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/vcrypt/vcrypt"
+)
+
+var (
+	rekeyFS = flag.NewFlagSet("rekey", flag.ExitOnError)
+
+	rekeyVars = struct {
+		in, out, plan, requireSeal, sealPolicy *string
+
+		dbDir, pgpDir, sshDir, sshKey, keyDir, ageID, pkcs11Module *string
+
+		secretConfig *string
+		secrets      *Provider
+	}{
+		in:   rekeyFS.String("in", "", "vault file - default stdin"),
+		out:  rekeyFS.String("out", "", "output file - default stdout"),
+		plan: rekeyFS.String("plan", "", "new plan file"),
+
+		requireSeal: rekeyFS.String("require-seal", "", "require a new plan seal by OpenPGP or SSH fingerprint"),
+		sealPolicy:  rekeyFS.String("seal-policy", "", "seal policy file of trusted new plan signers"),
+
+		dbDir:  rekeyFS.String("db.dir", "~/.vcrypt/db", "vcrypt database directory"),
+		pgpDir: rekeyFS.String("openpgp.dir", "~/.gnupg", "OpenPGP keyring directory"),
+		sshDir: rekeyFS.String("ssh.dir", "~/.ssh", "SSH key directory"),
+		sshKey: rekeyFS.String("ssh.key", "", "SSH private key file - default ssh.dir/id_*"),
+
+		keyDir: rekeyFS.String("key.dir", "~/.vcrypt/keys", "box & hybrid-kem key directory"),
+		ageID:  rekeyFS.String("age.identity", "~/.config/age/keys.txt", "age identity file"),
+
+		pkcs11Module: rekeyFS.String("pkcs11.module", "", "PKCS#11 module for pkcs11-key tokens, e.g. libsofthsm2.so"),
+
+		secretConfig: rekeyFS.String("secret.config", "", "secret provider config file"),
+		secrets:      providerFlag(rekeyFS),
+	}
+)
+
+func rekey(args []string) {
+	rekeyFS.Parse(args)
+
+	var (
+		err error
+		vr  io.Reader
+		w   io.WriteCloser
+
+		in    = *rekeyVars.in
+		out   = *rekeyVars.out
+		pfile = *rekeyVars.plan
+		rseal = *rekeyVars.requireSeal
+		spol  = *rekeyVars.sealPolicy
+
+		dbDir  = *rekeyVars.dbDir
+		pgpDir = *rekeyVars.pgpDir
+		sshDir = *rekeyVars.sshDir
+		sshKey = *rekeyVars.sshKey
+
+		keyDir = *rekeyVars.keyDir
+		ageID  = *rekeyVars.ageID
+
+		pkcs11Module = *rekeyVars.pkcs11Module
+
+		secretConfig = *rekeyVars.secretConfig
+		provider     = rekeyVars.secrets
+	)
+
+	if pfile == "" {
+		fmt.Fprintln(os.Stderr, "missing required argument: -plan")
+		os.Exit(1)
+	}
+
+	if in == "" {
+		vr = os.Stdin
+	} else {
+		if vr, err = os.Open(in); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	data, err := ioutil.ReadAll(vr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	msg, _, err := vcrypt.Unarmor(data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	vault, ok := msg.(*vcrypt.Vault)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "could not load vault file")
+		os.Exit(1)
+	}
+
+	if data, err = ioutil.ReadFile(pfile); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if msg, _, err = vcrypt.Unarmor(data); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	plan, ok := msg.(*vcrypt.Plan)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "could not load plan file")
+		os.Exit(1)
+	}
+
+	pols, err := sealPolicies(rseal, spol)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	for _, pol := range pols {
+		if err := plan.CheckSealPolicy(pol); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if err := provider.LoadConfig(secretConfig); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	drv := &Driver{
+		DB: &DB{
+			vault:   vault,
+			baseDir: dbDir,
+		},
+		OpenPGPKeyRing: &OpenPGPKeyRing{
+			homedir: pgpDir,
+		},
+		SSHKeyRing: &SSHKeyRing{
+			homedir: sshDir,
+			keyfile: sshKey,
+		},
+		SSHAgent: &SSHAgent{
+			sock: os.Getenv("SSH_AUTH_SOCK"),
+		},
+		KeyDir: &KeyDir{
+			homedir: keyDir,
+		},
+		AgeKeyRing: &AgeKeyRing{
+			identityFile: ageID,
+		},
+		PKCS11: &PKCS11{
+			module: pkcs11Module,
+		},
+
+		provider: provider,
+	}
+
+	if err := vault.Rekey(plan, drv); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if data, err = vcrypt.Armor(vault); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if out == "" {
+		w = os.Stdout
+	} else {
+		if w, err = os.Create(out); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if err := drv.commit(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+
+		if err := drv.rollback(); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+		}
+
+		os.Exit(1)
+	}
+}
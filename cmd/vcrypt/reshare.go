@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/vcrypt/vcrypt"
+)
+
+var (
+	reshareFS = flag.NewFlagSet("reshare", flag.ExitOnError)
+
+	reshareVars = struct {
+		in, out, node, plan *string
+
+		dbDir, pgpDir, sshDir, sshKey, keyDir, ageID, pkcs11Module *string
+
+		secretConfig *string
+		secrets      *Provider
+	}{
+		in:   reshareFS.String("in", "", "vault file - default stdin"),
+		out:  reshareFS.String("out", "", "output file - default stdout"),
+		node: reshareFS.String("node", "", "id of the node to replace, as shown by inspect"),
+		plan: reshareFS.String("plan", "", "plan file of the replacement node"),
+
+		dbDir:  reshareFS.String("db.dir", "~/.vcrypt/db", "vcrypt database directory"),
+		pgpDir: reshareFS.String("openpgp.dir", "~/.gnupg", "OpenPGP keyring directory"),
+		sshDir: reshareFS.String("ssh.dir", "~/.ssh", "SSH key directory"),
+		sshKey: reshareFS.String("ssh.key", "", "SSH private key file - default ssh.dir/id_*"),
+
+		keyDir: reshareFS.String("key.dir", "~/.vcrypt/keys", "box & hybrid-kem key directory"),
+		ageID:  reshareFS.String("age.identity", "~/.config/age/keys.txt", "age identity file"),
+
+		pkcs11Module: reshareFS.String("pkcs11.module", "", "PKCS#11 module for pkcs11-key tokens, e.g. libsofthsm2.so"),
+
+		secretConfig: reshareFS.String("secret.config", "", "secret provider config file"),
+		secrets:      providerFlag(reshareFS),
+	}
+)
+
+func reshare(args []string) {
+	reshareFS.Parse(args)
+
+	var (
+		err error
+		vr  io.Reader
+		w   io.WriteCloser
+
+		in    = *reshareVars.in
+		out   = *reshareVars.out
+		node  = *reshareVars.node
+		pfile = *reshareVars.plan
+
+		dbDir  = *reshareVars.dbDir
+		pgpDir = *reshareVars.pgpDir
+		sshDir = *reshareVars.sshDir
+		sshKey = *reshareVars.sshKey
+
+		keyDir = *reshareVars.keyDir
+		ageID  = *reshareVars.ageID
+
+		pkcs11Module = *reshareVars.pkcs11Module
+
+		secretConfig = *reshareVars.secretConfig
+		provider     = reshareVars.secrets
+	)
+
+	if node == "" {
+		fmt.Fprintln(os.Stderr, "missing required argument: -node")
+		os.Exit(1)
+	}
+	if pfile == "" {
+		fmt.Fprintln(os.Stderr, "missing required argument: -plan")
+		os.Exit(1)
+	}
+
+	nodeID, err := hex.DecodeString(node)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if in == "" {
+		vr = os.Stdin
+	} else {
+		if vr, err = os.Open(in); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	data, err := ioutil.ReadAll(vr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	msg, _, err := vcrypt.Unarmor(data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	vault, ok := msg.(*vcrypt.Vault)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "could not load vault file")
+		os.Exit(1)
+	}
+
+	if data, err = ioutil.ReadFile(pfile); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if msg, _, err = vcrypt.Unarmor(data); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	plan, ok := msg.(*vcrypt.Plan)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "could not load plan file")
+		os.Exit(1)
+	}
+
+	if err := provider.LoadConfig(secretConfig); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	drv := &Driver{
+		DB: &DB{
+			vault:   vault,
+			baseDir: dbDir,
+		},
+		OpenPGPKeyRing: &OpenPGPKeyRing{
+			homedir: pgpDir,
+		},
+		SSHKeyRing: &SSHKeyRing{
+			homedir: sshDir,
+			keyfile: sshKey,
+		},
+		SSHAgent: &SSHAgent{
+			sock: os.Getenv("SSH_AUTH_SOCK"),
+		},
+		KeyDir: &KeyDir{
+			homedir: keyDir,
+		},
+		AgeKeyRing: &AgeKeyRing{
+			identityFile: ageID,
+		},
+		PKCS11: &PKCS11{
+			module: pkcs11Module,
+		},
+
+		provider: provider,
+	}
+
+	if err := vault.Reshare(nodeID, plan, drv); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if data, err = vcrypt.Armor(vault); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if out == "" {
+		w = os.Stdout
+	} else {
+		if w, err = os.Create(out); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if err := drv.commit(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+
+		if err := drv.rollback(); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+		}
+
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/bgentry/speakeasy"
+	"github.com/vcrypt/vcrypt"
+	"github.com/vcrypt/vcrypt/seal"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	sealFS = flag.NewFlagSet("seal", flag.ExitOnError)
+
+	sealVars = struct {
+		in, out, pgpKey, edKey, sshKey *string
+
+		dbDir, pgpDir *string
+	}{
+		in:     sealFS.String("in", "", "plan or vault file - default stdin"),
+		out:    sealFS.String("out", "", "output file - default stdout"),
+		pgpKey: sealFS.String("openpgp.key", "", "OpenPGP signing key ID"),
+		edKey:  sealFS.String("ed25519.key", "", "raw or OpenSSH Ed25519 private key file"),
+		sshKey: sealFS.String("ssh.key", "", "SSH private key file for an SSHSIG seal"),
+
+		dbDir:  sealFS.String("db.dir", "~/.vcrypt/db", "vcrypt database directory"),
+		pgpDir: sealFS.String("openpgp.dir", "~/.gnupg", "OpenPGP keyring directory"),
+	}
+)
+
+func sealM(args []string) {
+	sealFS.Parse(args)
+
+	var (
+		err error
+		r   io.Reader
+		w   io.WriteCloser
+
+		in     = *sealVars.in
+		out    = *sealVars.out
+		pgpKey = *sealVars.pgpKey
+		edKey  = *sealVars.edKey
+		sshKey = *sealVars.sshKey
+
+		dbDir  = *sealVars.dbDir
+		pgpDir = *sealVars.pgpDir
+	)
+
+	nkeys := 0
+	for _, key := range []string{pgpKey, edKey, sshKey} {
+		if key != "" {
+			nkeys++
+		}
+	}
+
+	if nkeys == 0 {
+		fmt.Fprintln(os.Stderr, "missing required argument: -openpgp.key, -ed25519.key, or -ssh.key")
+		os.Exit(1)
+	}
+
+	if nkeys > 1 {
+		fmt.Fprintln(os.Stderr, "conflicting arguments: -openpgp.key, -ed25519.key, & -ssh.key")
+		os.Exit(1)
+	}
+
+	if in == "" {
+		r = os.Stdin
+	} else {
+		if r, err = os.Open(in); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	msg, _, err := vcrypt.Unarmor(data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	var slr vcrypt.Sealer
+	switch {
+	case edKey != "":
+		slr, err = loadEd25519Sealer(edKey)
+	case sshKey != "":
+		slr, err = loadSSHSigSealer(sshKey)
+	default:
+		slr, err = loadOpenPGPSealer(pgpKey, pgpDir)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	switch msg := msg.(type) {
+	case *vcrypt.Plan:
+		if err := msg.CheckSeals(); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		if _, err := msg.AddSeal(slr); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	case *vcrypt.Vault:
+		if err := msg.CheckSeals(); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		// the vault digest changes with each seal, so move any stored
+		// material to the new database directory.
+		db := &DB{
+			vault:   msg,
+			baseDir: dbDir,
+		}
+
+		oldDir, err := db.dir()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		if _, err := msg.AddSeal(slr); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		newDir, err := db.dir()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		if err := os.Rename(oldDir, newDir); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, "could not load plan or vault file")
+		os.Exit(1)
+	}
+
+	if data, err = vcrypt.Armor(msg); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if out == "" {
+		w = os.Stdout
+	} else {
+		if w, err = os.Create(out); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if _, err := w.Write(data); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+func loadOpenPGPSealer(strID, homedir string) (vcrypt.Sealer, error) {
+	id, err := strconv.ParseUint(strID, 16, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	keyRing := &OpenPGPKeyRing{
+		homedir: homedir,
+	}
+
+	return keyRing.Sealer(id)
+}
+
+func loadEd25519Sealer(keyfile string) (vcrypt.Sealer, error) {
+	path, err := expandPath(keyfile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := seal.ParseEd25519Key(data)
+	if _, ok := err.(*ssh.PassphraseMissingError); ok {
+		prompt := fmt.Sprintf("passphrase for Ed25519 key %q: ", path)
+		pass, perr := speakeasy.FAsk(os.Stderr, prompt)
+		if perr != nil {
+			return nil, perr
+		}
+
+		key, err = seal.ParseEd25519KeyWithPassphrase(data, []byte(pass))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return seal.NewEd25519Sealer(key), nil
+}
+
+func loadSSHSigSealer(keyfile string) (vcrypt.Sealer, error) {
+	path, err := expandPath(keyfile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := ssh.ParsePrivateKey(data)
+	if _, ok := err.(*ssh.PassphraseMissingError); ok {
+		prompt := fmt.Sprintf("passphrase for ssh key %q: ", path)
+		pass, perr := speakeasy.FAsk(os.Stderr, prompt)
+		if perr != nil {
+			return nil, perr
+		}
+
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(data, []byte(pass))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return seal.NewSSHSigSealer(signer), nil
+}
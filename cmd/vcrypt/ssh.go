@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bgentry/speakeasy"
+	"github.com/vcrypt/vcrypt/secret"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// SSHKeyRing is a set of SSH private keys located on the local filesystem.
+// Either a single key file or the id_* key files in a directory are searched.
+type SSHKeyRing struct {
+	homedir string
+	keyfile string
+}
+
+// LoadPrivateKey loads the private key data for the first key file matching
+// the secret's fingerprint. Encrypted keys are first decrypted with a
+// passphrase. Unreadable id_* files in the directory are skipped. No data is
+// returned if a matching key is not found.
+func (r *SSHKeyRing) LoadPrivateKey(sec *secret.SSHKey) ([][]byte, error) {
+	paths, err := r.keyPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			if r.keyfile == "" {
+				continue
+			}
+			return nil, err
+		}
+
+		pub, encrypted := publicKey(path, data)
+		if pub == nil || !sec.Match(pub) {
+			continue
+		}
+
+		if !encrypted {
+			return sec.Load(bytes.NewReader(data))
+		}
+
+		prompt := fmt.Sprintf("passphrase for ssh key %q: ", path)
+		pass, err := speakeasy.FAsk(os.Stderr, prompt)
+		if err != nil {
+			return nil, err
+		}
+
+		return sec.LoadWithPassphrase(bytes.NewReader(data), []byte(pass))
+	}
+
+	return nil, nil
+}
+
+func (r *SSHKeyRing) keyPaths() ([]string, error) {
+	if r.keyfile != "" {
+		path, err := expandPath(r.keyfile)
+		if err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+
+	pattern, err := expandPath(r.homedir, "id_*")
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(matches))
+	for _, path := range matches {
+		if !strings.HasSuffix(path, ".pub") {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// publicKey returns the public key for the private key data, from the
+// neighboring .pub file if the private key is encrypted.
+func publicKey(path string, data []byte) (pub ssh.PublicKey, encrypted bool) {
+	signer, err := ssh.ParsePrivateKey(data)
+	if err == nil {
+		return signer.PublicKey(), false
+	}
+
+	if perr, ok := err.(*ssh.PassphraseMissingError); ok {
+		if perr.PublicKey != nil {
+			return perr.PublicKey, true
+		}
+
+		if data, err := ioutil.ReadFile(path + ".pub"); err == nil {
+			if pub, _, _, _, err := ssh.ParseAuthorizedKey(data); err == nil {
+				return pub, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// SSHAgent is a running ssh-agent listening on a unix socket.
+type SSHAgent struct {
+	sock string
+}
+
+// SignChallenge loads the secret data from the agent's signature of the
+// secret's challenge. No data is returned if the agent is not running or does
+// not hold the key, unless the vault is being locked, in which case an error
+// is returned.
+func (a *SSHAgent) SignChallenge(sec *secret.SSHAgent, lock bool) ([][]byte, error) {
+	if a.sock == "" {
+		if lock {
+			return nil, fmt.Errorf("ssh-agent secret '%s' requires a running ssh-agent (SSH_AUTH_SOCK)", sec.Comment())
+		}
+		return nil, nil
+	}
+
+	conn, err := net.Dial("unix", a.sock)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	sig, err := sec.Sign(agent.NewClient(conn))
+	if err != nil {
+		return nil, err
+	}
+	if sig == nil {
+		if lock {
+			return nil, fmt.Errorf("ssh-agent does not hold the key for secret '%s'", sec.Comment())
+		}
+		return nil, nil
+	}
+
+	return sec.Load(bytes.NewReader(sig))
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vcrypt/vcrypt/secret"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func TestSSHKeyRingLoadPrivateKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ssh.NewPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sec, err := secret.NewSSHKey(ssh.FingerprintSHA256(pub), "ssh key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// id_a is an unreadable candidate ahead of the matching id_ecdsa
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "id_a"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	if err := ioutil.WriteFile(filepath.Join(dir, "id_ecdsa"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := (&SSHKeyRing{homedir: dir}).LoadPrivateKey(sec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || len(keys[0]) == 0 {
+		t.Errorf("want private key data, got %x", keys)
+	}
+
+	if _, err := (&SSHKeyRing{keyfile: filepath.Join(dir, "id_a")}).LoadPrivateKey(sec); err == nil {
+		t.Errorf("want error for unreadable key file, got nil")
+	}
+}
+
+func TestSSHAgentSignChallenge(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sec, err := secret.NewSSHAgent(ssh.FingerprintSHA256(sshPub), "agent key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// no agent running
+	if data, err := (&SSHAgent{}).SignChallenge(sec, false); err != nil || data != nil {
+		t.Errorf("want skip without agent at unlock, got %x, %v", data, err)
+	}
+	if _, err := (&SSHAgent{}).SignChallenge(sec, true); err == nil {
+		t.Errorf("want lock error without agent, got nil")
+	}
+
+	keyring := agent.NewKeyring()
+	sock := serveAgent(t, keyring)
+
+	// agent without the key
+	if data, err := (&SSHAgent{sock: sock}).SignChallenge(sec, false); err != nil || data != nil {
+		t.Errorf("want skip for missing agent key at unlock, got %x, %v", data, err)
+	}
+	if _, err := (&SSHAgent{sock: sock}).SignChallenge(sec, true); err == nil {
+		t.Errorf("want lock error for missing agent key, got nil")
+	}
+
+	if err := keyring.Add(agent.AddedKey{PrivateKey: priv}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := (&SSHAgent{sock: sock}).SignChallenge(sec, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || len(data[0]) == 0 {
+		t.Errorf("want secret data from agent key, got %x", data)
+	}
+}
+
+// serveAgent serves the agent on a unix socket for the duration of the test.
+func serveAgent(t *testing.T, a agent.Agent) string {
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				agent.ServeAgent(a, conn)
+			}()
+		}
+	}()
+	return sock
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/vcrypt/vcrypt"
+	"github.com/vcrypt/vcrypt/cli"
+)
+
+var (
+	statusFS = flag.NewFlagSet("status", flag.ExitOnError)
+
+	statusVars = struct {
+		in *string
+
+		dbDir *string
+	}{
+		in: statusFS.String("in", "", "vault file - default stdin"),
+
+		dbDir: statusFS.String("db.dir", "~/.vcrypt/db", "vcrypt database directory"),
+	}
+)
+
+func status(args []string) {
+	statusFS.Parse(args)
+
+	var (
+		err error
+		r   io.Reader
+
+		in = *statusVars.in
+	)
+
+	if in == "" {
+		r = os.Stdin
+	} else {
+		if r, err = os.Open(in); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	msg, _, err := vcrypt.Unarmor(data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	vault, ok := msg.(*vcrypt.Vault)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "could not load vault file")
+		os.Exit(1)
+	}
+
+	db := &DB{
+		vault:   vault,
+		baseDir: *statusVars.dbDir,
+	}
+
+	lines, steps, err := cli.VaultStatus(vault, db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+	fmt.Println("next steps:")
+	for _, step := range steps {
+		fmt.Printf("  - %s\n", step)
+	}
+}
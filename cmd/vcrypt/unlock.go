@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/vcrypt/vcrypt"
 )
@@ -14,15 +15,34 @@ var (
 	unlockFS = flag.NewFlagSet("unlock", flag.ExitOnError)
 
 	unlockVars = struct {
-		in, out *string
+		in, out, detach, stream, requireSeal, sealPolicy *string
 
-		dbDir, pgpDir *string
+		dbDir, pgpDir, sshDir, sshKey, keyDir, ageID, pkcs11Module *string
+
+		secretConfig *string
+		secrets      *Provider
 	}{
 		in:  unlockFS.String("in", "", "vault file - default stdin"),
-		out: unlockFS.String("out", "", "output file - default stdout"),
+		out: unlockFS.String("out", "", "output file, written only if the unlock succeeds - default stdout"),
+
+		detach: unlockFS.String("detach", "", "detached payload file"),
+		stream: unlockFS.String("stream", "", "streamed payload file"),
+
+		requireSeal: unlockFS.String("require-seal", "", "require a vault or plan seal by OpenPGP or SSH fingerprint"),
+		sealPolicy:  unlockFS.String("seal-policy", "", "seal policy file of trusted vault & plan signers"),
 
 		dbDir:  unlockFS.String("db.dir", "~/.vcrypt/db", "vcrypt database directory"),
 		pgpDir: unlockFS.String("openpgp.dir", "~/.gnupg", "OpenPGP keyring directory"),
+		sshDir: unlockFS.String("ssh.dir", "~/.ssh", "SSH key directory"),
+		sshKey: unlockFS.String("ssh.key", "", "SSH private key file - default ssh.dir/id_*"),
+
+		keyDir: unlockFS.String("key.dir", "~/.vcrypt/keys", "box & hybrid-kem key directory"),
+		ageID:  unlockFS.String("age.identity", "~/.config/age/keys.txt", "age identity file"),
+
+		pkcs11Module: unlockFS.String("pkcs11.module", "", "PKCS#11 module for pkcs11-key tokens, e.g. libsofthsm2.so"),
+
+		secretConfig: unlockFS.String("secret.config", "", "secret provider config file"),
+		secrets:      providerFlag(unlockFS),
 	}
 )
 
@@ -37,10 +57,30 @@ func unlock(args []string) {
 		in  = *unlockVars.in
 		out = *unlockVars.out
 
+		dfile = *unlockVars.detach
+		sfile = *unlockVars.stream
+		rseal = *unlockVars.requireSeal
+		spol  = *unlockVars.sealPolicy
+
 		dbDir  = *unlockVars.dbDir
 		pgpDir = *unlockVars.pgpDir
+		sshDir = *unlockVars.sshDir
+		sshKey = *unlockVars.sshKey
+
+		keyDir = *unlockVars.keyDir
+		ageID  = *unlockVars.ageID
+
+		pkcs11Module = *unlockVars.pkcs11Module
+
+		secretConfig = *unlockVars.secretConfig
+		provider     = unlockVars.secrets
 	)
 
+	if dfile != "" && sfile != "" {
+		fmt.Fprintln(os.Stderr, "conflicting arguments: -detach & -stream")
+		os.Exit(1)
+	}
+
 	if in == "" {
 		vr = os.Stdin
 	} else {
@@ -50,15 +90,6 @@ func unlock(args []string) {
 		}
 	}
 
-	if out == "" {
-		w = os.Stdout
-	} else {
-		if w, err = os.Create(out); err != nil {
-			fmt.Fprintln(os.Stderr, err.Error())
-			os.Exit(1)
-		}
-	}
-
 	data, err := ioutil.ReadAll(vr)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
@@ -76,6 +107,23 @@ func unlock(args []string) {
 		os.Exit(1)
 	}
 
+	pols, err := sealPolicies(rseal, spol)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	for _, pol := range pols {
+		if err := vault.CheckSealPolicy(pol); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if err := provider.LoadConfig(secretConfig); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
 	drv := &Driver{
 		DB: &DB{
 			vault:   vault,
@@ -84,15 +132,60 @@ func unlock(args []string) {
 		OpenPGPKeyRing: &OpenPGPKeyRing{
 			homedir: pgpDir,
 		},
+		SSHKeyRing: &SSHKeyRing{
+			homedir: sshDir,
+			keyfile: sshKey,
+		},
+		SSHAgent: &SSHAgent{
+			sock: os.Getenv("SSH_AUTH_SOCK"),
+		},
+		KeyDir: &KeyDir{
+			homedir: keyDir,
+		},
+		AgeKeyRing: &AgeKeyRing{
+			identityFile: ageID,
+		},
+		PKCS11: &PKCS11{
+			module: pkcs11Module,
+		},
+
+		provider: provider,
+	}
+
+	pfile := dfile
+	if sfile != "" {
+		pfile = sfile
+	}
+	if pfile != "" {
+		if drv.pr, err = os.Open(pfile); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		defer drv.pr.Close()
+	}
+
+	// a stream payload is written as it is decrypted, so the output is only
+	// moved into place once the whole payload is authenticated
+	var of *outputFile
+	if out == "" {
+		w = os.Stdout
+	} else {
+		if of, err = createOutput(out); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		w = of
 	}
 
 	unlocked, err := vault.Unlock(w, drv)
 	if err != nil {
+		of.discard()
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
 
 	if err := drv.commit(); err != nil {
+		of.discard()
 		if err := drv.rollback(); err != nil {
 			fmt.Fprintln(os.Stderr, err.Error())
 		}
@@ -102,6 +195,49 @@ func unlock(args []string) {
 	}
 
 	if !unlocked {
+		of.discard()
 		os.Exit(1)
 	}
+
+	if err := of.commit(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// outputFile is a temporary file in the directory of the output path that
+// replaces the output path on commit.
+type outputFile struct {
+	*os.File
+
+	path string
+}
+
+func createOutput(path string) (*outputFile, error) {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return nil, err
+	}
+	return &outputFile{File: f, path: path}, nil
+}
+
+// commit moves the temporary file to the output path.
+func (f *outputFile) commit() error {
+	if f == nil {
+		return nil
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), f.path)
+}
+
+// discard removes the temporary file.
+func (f *outputFile) discard() {
+	if f == nil {
+		return
+	}
+	f.Close()
+	os.Remove(f.Name())
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret.txt")
+
+	of, err := createOutput(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := of.Write([]byte("partial")); err != nil {
+		t.Fatal(err)
+	}
+	of.discard()
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("want no output after discard, got %v", err)
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Errorf("want no temporary files after discard, got %d", len(files))
+	}
+
+	if of, err = createOutput(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := of.Write([]byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+	if err := of.commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "secret", string(data); want != got {
+		t.Errorf("want output %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vcrypt/vcrypt"
+	"github.com/vcrypt/vcrypt/seal"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	verifyFS = flag.NewFlagSet("verify", flag.ExitOnError)
+
+	verifyVars = struct {
+		in, requireSeal, sealPolicy, allowedSigners *string
+	}{
+		in:          verifyFS.String("in", "", "plan or vault file - default stdin"),
+		requireSeal: verifyFS.String("require-seal", "", "require a seal by OpenPGP or SSH fingerprint"),
+		sealPolicy:  verifyFS.String("seal-policy", "", "seal policy file of trusted signers"),
+
+		allowedSigners: verifyFS.String("ssh.allowed-signers", "", "allowed_signers file for SSHSIG seal signers"),
+	}
+)
+
+func verify(args []string) {
+	verifyFS.Parse(args)
+
+	var (
+		err   error
+		r     io.Reader
+		seals []seal.Seal
+
+		in         = *verifyVars.in
+		requireKey = *verifyVars.requireSeal
+		policyFile = *verifyVars.sealPolicy
+		signers    = *verifyVars.allowedSigners
+	)
+
+	if in == "" {
+		r = os.Stdin
+	} else {
+		if r, err = os.Open(in); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	msg, _, err := vcrypt.Unarmor(data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	switch msg := msg.(type) {
+	case *vcrypt.Plan:
+		seals = verifyPlan(msg)
+	case *vcrypt.Vault:
+		seals = verifyVault(msg)
+	default:
+		fmt.Fprintln(os.Stderr, "could not load plan or vault file")
+		os.Exit(1)
+	}
+
+	if len(seals) == 0 {
+		fmt.Fprintln(os.Stderr, "no seals found")
+		os.Exit(1)
+	}
+
+	pols, err := sealPolicies(requireKey, policyFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	for _, pol := range pols {
+		if err := pol.Check(seals); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if signers != "" {
+		if err := checkAllowedSigners(signers, seals); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+}
+
+// checkAllowedSigners returns an error unless the signer of each SSHSig seal
+// is listed in the allowed_signers file.
+func checkAllowedSigners(path string, seals []seal.Seal) error {
+	path, err := expandPath(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	signers, err := seal.ParseAllowedSigners(data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, s := range seals {
+		s, ok := s.(*seal.SSHSig)
+		if !ok {
+			continue
+		}
+
+		pub, err := s.PublicKey()
+		if err != nil {
+			return err
+		}
+
+		var principals []string
+		for _, signer := range signers {
+			if signer.Allows(pub, now) {
+				principals = append(principals, signer.Principals...)
+			}
+		}
+		if len(principals) == 0 {
+			return fmt.Errorf("seal by %s is not an allowed signer", ssh.FingerprintSHA256(pub))
+		}
+
+		fmt.Printf("seal by %s: allowed signer %s\n", ssh.FingerprintSHA256(pub), strings.Join(principals, ", "))
+	}
+	return nil
+}
+
+func verifyPlan(plan *vcrypt.Plan) []seal.Seal {
+	if err := plan.CheckSeals(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	seals, err := plan.Seals()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	printSeals("plan", seals)
+	return seals
+}
+
+func verifyVault(vault *vcrypt.Vault) []seal.Seal {
+	if err := vault.CheckSeals(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	seals, err := vault.Plan.Seals()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	printSeals("plan", seals)
+
+	vseals, err := vault.Seals()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	printSeals("vault", vseals)
+
+	return append(seals, vseals...)
+}
+
+func printSeals(kind string, seals []seal.Seal) {
+	for _, s := range seals {
+		ids, err := seal.Identities(s)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Printf("%s seal by %s: OK\n", kind, strings.Join(ids, ", "))
+	}
+}
@@ -0,0 +1,437 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AccessPolicy config. The formula is a boolean & threshold expression over
+// node names, e.g. "(alice box and bob box) or (2 of claire, david, eve)".
+// An "of" operand list extends to the closing parenthesis or the end of the
+// formula.
+type AccessPolicy struct {
+	Comment string `vcrypt:"comment,optional"`
+	Formula string `vcrypt:"formula"`
+}
+
+// ExpandAccessPolicies lowers each access policy into xor, mux, sss, & demux
+// sections. The top node of a policy takes the policy name & inner nodes are
+// named after the policy and numbered. Operands used more than once are
+// shared behind a demux.
+func (p *Plan) ExpandAccessPolicies() error {
+	names := make([]string, 0, len(p.AccessPolicies))
+	for name := range p.AccessPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pol := p.AccessPolicies[name]
+
+		f, err := parseFormula(pol.Formula)
+		if err != nil {
+			return fmt.Errorf("policy %q: %s", name, err)
+		}
+		if f = f.simplify(); f.name != "" {
+			return fmt.Errorf("policy %q: formula requires an and, or, or of operator", name)
+		}
+
+		lwr := &lowering{
+			plan:   p,
+			policy: name,
+			refs:   map[string]int{},
+			nodes:  map[string]string{},
+		}
+		f.count(lwr.refs)
+
+		comment := pol.Comment
+		if comment == "" {
+			comment = f.String()
+		}
+		if _, err := lwr.lower(f, name, comment); err != nil {
+			return fmt.Errorf("policy %q: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// formula is a k of n threshold over the operands, or a named leaf.
+type formula struct {
+	name string
+
+	k   int
+	ops []*formula
+}
+
+func (f *formula) and() bool { return f.name == "" && f.k == len(f.ops) }
+func (f *formula) or() bool  { return f.name == "" && f.k == 1 }
+
+// simplify flattens nested and & or operators, drops duplicate operands, &
+// replaces single operand formulas by the operand.
+func (f *formula) simplify() *formula {
+	if f.name != "" {
+		return f
+	}
+
+	and, or := f.and(), f.or()
+
+	var (
+		ops  []*formula
+		seen = map[string]bool{}
+	)
+	for _, op := range f.ops {
+		op = op.simplify()
+
+		sub := []*formula{op}
+		if (and && op.and()) || (or && op.or()) {
+			sub = op.ops
+		}
+		for _, op := range sub {
+			if s := op.String(); !seen[s] || (!and && !or) {
+				seen[s] = true
+				ops = append(ops, op)
+			}
+		}
+	}
+
+	switch {
+	case and:
+		f = &formula{k: len(ops), ops: ops}
+	case or:
+		f = &formula{k: 1, ops: ops}
+	default:
+		f = &formula{k: f.k, ops: ops}
+	}
+
+	if len(f.ops) == 1 {
+		return f.ops[0]
+	}
+	return f
+}
+
+// count tallies the references to each operand.
+func (f *formula) count(refs map[string]int) {
+	for _, op := range f.ops {
+		if refs[op.String()]++; refs[op.String()] == 1 {
+			op.count(refs)
+		}
+	}
+}
+
+func (f *formula) String() string {
+	if f.name != "" {
+		return f.name
+	}
+
+	ops := make([]string, 0, len(f.ops))
+	for _, op := range f.ops {
+		if op.name != "" {
+			ops = append(ops, op.name)
+		} else {
+			ops = append(ops, "("+op.String()+")")
+		}
+	}
+
+	switch {
+	case f.and():
+		return strings.Join(ops, " and ")
+	case f.or():
+		return strings.Join(ops, " or ")
+	default:
+		return fmt.Sprintf("%d of %s", f.k, strings.Join(ops, ", "))
+	}
+}
+
+type lowering struct {
+	plan   *Plan
+	policy string
+	seq    int
+
+	refs  map[string]int    // operand references by formula
+	nodes map[string]string // lowered node names by formula
+}
+
+// lower adds the sections for f & returns the node name to use as an edge.
+func (l *lowering) lower(f *formula, name, comment string) (string, error) {
+	if f.name != "" {
+		return f.name, nil
+	}
+
+	edges := make([]string, 0, len(f.ops))
+	for _, op := range f.ops {
+		edge, err := l.operand(op)
+		if err != nil {
+			return "", err
+		}
+		edges = append(edges, edge)
+	}
+
+	if err := l.plan.checkName(name); err != nil {
+		return "", err
+	}
+
+	switch {
+	case f.and():
+		if l.plan.XORs == nil {
+			l.plan.XORs = map[string]XOR{}
+		}
+		l.plan.XORs[name] = XOR{Comment: comment, EdgeSlice: edges}
+	case f.or():
+		if l.plan.Muxes == nil {
+			l.plan.Muxes = map[string]Mux{}
+		}
+		l.plan.Muxes[name] = Mux{Comment: comment, EdgeSlice: edges}
+	default:
+		if f.k > 255 || len(f.ops) > 255 {
+			return "", errors.New("threshold formulas support at most 255 operands")
+		}
+		if l.plan.SSSs == nil {
+			l.plan.SSSs = map[string]SSS{}
+		}
+		l.plan.SSSs[name] = SSS{Comment: comment, EdgeSlice: edges, N: len(f.ops), K: f.k}
+	}
+	return name, nil
+}
+
+// operand lowers an operand of a formula. An operand referenced more than
+// once is lowered once behind a demux.
+func (l *lowering) operand(f *formula) (string, error) {
+	key := f.String()
+	if name, ok := l.nodes[key]; ok {
+		return name, nil
+	}
+
+	name := f.name
+	if name == "" {
+		var err error
+		if name, err = l.lower(f, l.next(), key); err != nil {
+			return "", err
+		}
+	}
+
+	if l.refs[key] > 1 {
+		demux := l.next()
+		if err := l.plan.checkName(demux); err != nil {
+			return "", err
+		}
+		if l.plan.Demuxes == nil {
+			l.plan.Demuxes = map[string]Demux{}
+		}
+		l.plan.Demuxes[demux] = Demux{Comment: key, EdgeSlice: []string{name}}
+		name = demux
+	}
+
+	l.nodes[key] = name
+	return name, nil
+}
+
+func (l *lowering) next() string {
+	l.seq++
+	return fmt.Sprintf("%s %d", l.policy, l.seq)
+}
+
+// checkName returns an error if a node is already named name.
+func (p Plan) checkName(name string) error {
+	_, cryptex := p.CryptexNode(name)
+	_, secret := p.SecretNode(name)
+	_, material := p.Materials[name]
+	if cryptex || secret || material {
+		return fmt.Errorf("duplicate node name %q", name)
+	}
+	return nil
+}
+
+// parseFormula parses an access policy formula:
+//
+//	expr   = term { "or" term }
+//	term   = factor { "and" factor }
+//	factor = "(" expr ")" | number "of" list | name
+//	list   = ( "(" expr { "," expr } ")" | expr ) { "," expr }
+//
+// Names are one or more words. Keywords are case insensitive.
+func parseFormula(s string) (*formula, error) {
+	fp := &formulaParser{toks: tokenizeFormula(s)}
+
+	f, err := fp.expr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := fp.peek(); tok != "" {
+		return nil, fmt.Errorf("unexpected %q in formula", tok)
+	}
+	return f, nil
+}
+
+type formulaParser struct {
+	toks []string
+}
+
+func tokenizeFormula(s string) []string {
+	var (
+		toks []string
+		word strings.Builder
+	)
+	flush := func() {
+		if word.Len() > 0 {
+			toks = append(toks, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range s {
+		switch r {
+		case '(', ')', ',':
+			flush()
+			toks = append(toks, string(r))
+		case ' ', '\t', '\r', '\n':
+			flush()
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return toks
+}
+
+func (fp *formulaParser) peek() string {
+	if len(fp.toks) == 0 {
+		return ""
+	}
+	return fp.toks[0]
+}
+
+func (fp *formulaParser) next() string {
+	tok := fp.peek()
+	if tok != "" {
+		fp.toks = fp.toks[1:]
+	}
+	return tok
+}
+
+func (fp *formulaParser) keyword(kw string) bool {
+	return strings.EqualFold(fp.peek(), kw)
+}
+
+func (fp *formulaParser) expr() (*formula, error) {
+	return fp.binary("or", fp.term, func(ops []*formula) *formula {
+		return &formula{k: 1, ops: ops}
+	})
+}
+
+func (fp *formulaParser) term() (*formula, error) {
+	return fp.binary("and", fp.factor, func(ops []*formula) *formula {
+		return &formula{k: len(ops), ops: ops}
+	})
+}
+
+func (fp *formulaParser) binary(kw string, operand func() (*formula, error), op func([]*formula) *formula) (*formula, error) {
+	f, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	ops := []*formula{f}
+	for fp.keyword(kw) {
+		fp.next()
+		if f, err = operand(); err != nil {
+			return nil, err
+		}
+		ops = append(ops, f)
+	}
+
+	if len(ops) == 1 {
+		return ops[0], nil
+	}
+	return op(ops), nil
+}
+
+func (fp *formulaParser) factor() (*formula, error) {
+	switch tok := fp.peek(); {
+	case tok == "":
+		return nil, errors.New("unexpected end of formula")
+	case tok == "(":
+		fp.next()
+		f, err := fp.expr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := fp.next(); tok != ")" {
+			return nil, fmt.Errorf("expected ) in formula, got %q", tok)
+		}
+		return f, nil
+	case tok == ")" || tok == ",":
+		return nil, fmt.Errorf("unexpected %q in formula", tok)
+	}
+
+	if len(fp.toks) > 1 && strings.EqualFold(fp.toks[1], "of") {
+		k, err := strconv.Atoi(fp.peek())
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold %q in formula", fp.peek())
+		}
+		fp.toks = fp.toks[2:]
+		return fp.threshold(k)
+	}
+
+	var words []string
+	for tok := fp.peek(); tok != "" && tok != "(" && tok != ")" && tok != ","; tok = fp.peek() {
+		if fp.keyword("and") || fp.keyword("or") || fp.keyword("of") {
+			break
+		}
+		words = append(words, fp.next())
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("expected a name in formula, got %q", fp.peek())
+	}
+	return &formula{name: strings.Join(words, " ")}, nil
+}
+
+func (fp *formulaParser) threshold(k int) (*formula, error) {
+	var ops []*formula
+	if fp.peek() == "(" {
+		fp.next()
+		for {
+			f, err := fp.expr()
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, f)
+
+			if tok := fp.next(); tok == ")" {
+				break
+			} else if tok != "," {
+				return nil, fmt.Errorf("expected , or ) in formula, got %q", tok)
+			}
+		}
+	} else {
+		f, err := fp.expr()
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, f)
+	}
+
+	for fp.peek() == "," {
+		fp.next()
+		f, err := fp.expr()
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, f)
+	}
+
+	if k < 1 || k > len(ops) {
+		return nil, fmt.Errorf("threshold %d of %d operands out of range", k, len(ops))
+	}
+
+	seen := map[string]bool{}
+	for _, op := range ops {
+		if seen[op.String()] {
+			return nil, fmt.Errorf("duplicate operand %q in threshold", op.String())
+		}
+		seen[op.String()] = true
+	}
+	return &formula{k: k, ops: ops}, nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFormula(t *testing.T) {
+	tests := []struct {
+		formula, want string
+	}{
+		{"a and b", "a and b"},
+		{"alice box OR bob box", "alice box or bob box"},
+		{"(a and b) or (2 of c, d, e)", "(a and b) or (2 of c, d, e)"},
+		{"a and b or c", "(a and b) or c"},
+		{"a and (b and c)", "a and b and c"},
+		{"a or (b or a)", "a or b"},
+		{"2 of (a and b), c, d", "2 of (a and b), c, d"},
+		{"2 of (a, b, c) or d", "(2 of a, b, c) or d"},
+		{"1 of a, b", "a or b"},
+		{"3 of a, b, c", "a and b and c"},
+		{"(a)", "a"},
+	}
+
+	for _, test := range tests {
+		f, err := parseFormula(test.formula)
+		if err != nil {
+			t.Fatalf("%q: %s", test.formula, err)
+		}
+		if got := f.simplify().String(); test.want != got {
+			t.Errorf("%q: want %q, got %q", test.formula, test.want, got)
+		}
+	}
+
+	for _, formula := range []string{"", "a and", "(a or b", "a b)", "4 of a, b, c", "x of a, b", "2 of a, a, b", "a, b"} {
+		if _, err := parseFormula(formula); err == nil {
+			t.Errorf("%q: want parse error, got nil", formula)
+		}
+	}
+}
+
+func TestExpandAccessPolicies(t *testing.T) {
+	plan := Plan{
+		AccessPolicies: map[string]AccessPolicy{
+			"access": {Formula: "(a and b) or (2 of a, c, d)"},
+		},
+		SecretBoxes: map[string]SecretBox{
+			"a": {}, "b": {}, "c": {}, "d": {},
+		},
+	}
+
+	if err := plan.ExpandAccessPolicies(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := (Mux{Comment: "(a and b) or (2 of a, c, d)", EdgeSlice: []string{"access 1", "access 3"}}), plan.Muxes["access"]; !reflect.DeepEqual(want, got) {
+		t.Errorf("want mux %+v, got %+v", want, got)
+	}
+	if want, got := (XOR{Comment: "a and b", EdgeSlice: []string{"access 2", "b"}}), plan.XORs["access 1"]; !reflect.DeepEqual(want, got) {
+		t.Errorf("want xor %+v, got %+v", want, got)
+	}
+	if want, got := (Demux{Comment: "a", EdgeSlice: []string{"a"}}), plan.Demuxes["access 2"]; !reflect.DeepEqual(want, got) {
+		t.Errorf("want demux %+v, got %+v", want, got)
+	}
+	if want, got := (SSS{Comment: "2 of a, c, d", EdgeSlice: []string{"access 2", "c", "d"}, N: 3, K: 2}), plan.SSSs["access 3"]; !reflect.DeepEqual(want, got) {
+		t.Errorf("want sss %+v, got %+v", want, got)
+	}
+
+	plan.AccessPolicies = map[string]AccessPolicy{"a": {Formula: "b or c"}}
+	if err := plan.ExpandAccessPolicies(); err == nil {
+		t.Errorf("want error for duplicate node name, got nil")
+	}
+
+	plan.AccessPolicies = map[string]AccessPolicy{"single": {Formula: "a and a"}}
+	if err := plan.ExpandAccessPolicies(); err == nil {
+		t.Errorf("want error for formula without operator, got nil")
+	}
+}
@@ -4,15 +4,23 @@ import (
 	"bytes"
 	"crypto"
 	"crypto/rsa"
-	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"filippo.io/age"
 	"github.com/vcrypt/vcrypt/cryptex"
+	"github.com/vcrypt/vcrypt/seal"
 	"github.com/vcrypt/vcrypt/secret"
 	"golang.org/x/crypto/openpgp"
 	"golang.org/x/crypto/ssh"
@@ -33,14 +41,41 @@ type Plan struct {
 	OpenPGPs    map[string]OpenPGP   `vcrypt:"openpgp,section"`
 	Muxes       map[string]Mux       `vcrypt:"mux,section"`
 	Demuxes     map[string]Demux     `vcrypt:"demux,section"`
+	SSHs        map[string]SSH       `vcrypt:"ssh,section"`
+
+	KDFSecretBoxes map[string]KDFSecretBox `vcrypt:"kdf-secretbox,section"`
+	HybridKEMs     map[string]HybridKEM    `vcrypt:"hybrid-kem,section"`
+	Ages           map[string]Age          `vcrypt:"age,section"`
+	VSSs           map[string]VSS          `vcrypt:"vss,section"`
+	WeightedSSSs   map[string]WeightedSSS  `vcrypt:"weighted-sss,section"`
+	Thresholds     map[string]Threshold    `vcrypt:"threshold,section"`
+	TimeLocks      map[string]TimeLock     `vcrypt:"timelock,section"`
+	Derives        map[string]Derive       `vcrypt:"derive,section"`
+
+	// Access policy config
+	AccessPolicies map[string]AccessPolicy `vcrypt:"policy,section"`
 
 	// Secret config
 	Passwords   map[string]Password   `vcrypt:"password,section"`
 	OpenPGPKeys map[string]OpenPGPKey `vcrypt:"openpgp-key,section"`
 	SSHKeys     map[string]SSHKey     `vcrypt:"ssh-key,section"`
+	SSHAgents   map[string]SSHAgent   `vcrypt:"ssh-agent,section"`
+	X25519Keys  map[string]X25519Key  `vcrypt:"x25519-key,section"`
+
+	HybridKEMKeys map[string]HybridKEMKey `vcrypt:"hybrid-kem-key,section"`
+	AgeIdentities map[string]AgeIdentity  `vcrypt:"age-identity,section"`
+	PKCS11Keys    map[string]PKCS11Key    `vcrypt:"pkcs11-key,section"`
 
 	// Material config
 	Materials map[string]Marker `vcrypt:"material,section"`
+
+	// Seal config
+	Seals map[string]Seal `vcrypt:"seal,section"`
+}
+
+// Sealer constructs seals for plan data.
+type Sealer interface {
+	Seal(data []byte) (seal.Seal, error)
 }
 
 // CryptexNode config
@@ -85,6 +120,33 @@ func (p Plan) CryptexNode(name string) (CryptexNode, bool) {
 	if n, ok := p.Demuxes[name]; ok {
 		return n, true
 	}
+	if n, ok := p.SSHs[name]; ok {
+		return n, true
+	}
+	if n, ok := p.KDFSecretBoxes[name]; ok {
+		return n, true
+	}
+	if n, ok := p.HybridKEMs[name]; ok {
+		return n, true
+	}
+	if n, ok := p.Ages[name]; ok {
+		return n, true
+	}
+	if n, ok := p.VSSs[name]; ok {
+		return n, true
+	}
+	if n, ok := p.WeightedSSSs[name]; ok {
+		return n, true
+	}
+	if n, ok := p.Thresholds[name]; ok {
+		return n, true
+	}
+	if n, ok := p.TimeLocks[name]; ok {
+		return n, true
+	}
+	if n, ok := p.Derives[name]; ok {
+		return n, true
+	}
 
 	return nil, false
 }
@@ -100,6 +162,21 @@ func (p Plan) SecretNode(name string) (SecretNode, bool) {
 	if n, ok := p.SSHKeys[name]; ok {
 		return n, true
 	}
+	if n, ok := p.SSHAgents[name]; ok {
+		return n, true
+	}
+	if n, ok := p.X25519Keys[name]; ok {
+		return n, true
+	}
+	if n, ok := p.HybridKEMKeys[name]; ok {
+		return n, true
+	}
+	if n, ok := p.AgeIdentities[name]; ok {
+		return n, true
+	}
+	if n, ok := p.PKCS11Keys[name]; ok {
+		return n, true
+	}
 
 	return nil, false
 }
@@ -121,6 +198,159 @@ func (n SSS) Cryptex() (cryptex.Cryptex, error) {
 // Edges for SSS
 func (n SSS) Edges() []string { return n.EdgeSlice }
 
+// VSS config
+type VSS struct {
+	Comment   string   `vcrypt:"comment,optional"`
+	EdgeSlice []string `vcrypt:"edge,optional"`
+
+	N int `vcrypt:"max-shares"`
+	K int `vcrypt:"required-shares"`
+}
+
+// Cryptex for VSS
+func (n VSS) Cryptex() (cryptex.Cryptex, error) {
+	return cryptex.NewVSS(uint32(n.N), uint32(n.K), n.Comment), nil
+}
+
+// Edges for VSS. The last edge is the commitments material.
+func (n VSS) Edges() []string { return n.EdgeSlice }
+
+// WeightedSSS config. Edges are weighted with a trailing ":<weight>" suffix of
+// decimal digits, e.g. "ciso:2", and are weight 1 otherwise, e.g. "ops:east
+// key". An edge to a node named with a ":<digits>" suffix needs an explicit
+// weight, e.g. "vault:8200:1".
+type WeightedSSS struct {
+	Comment   string   `vcrypt:"comment,optional"`
+	EdgeSlice []string `vcrypt:"edge,optional"`
+
+	Threshold int `vcrypt:"required-weight"`
+}
+
+// Cryptex for WeightedSSS
+func (n WeightedSSS) Cryptex() (cryptex.Cryptex, error) {
+	weights := make([]uint32, 0, len(n.EdgeSlice))
+	for _, edge := range n.EdgeSlice {
+		_, weight, err := parseWeightedEdge(edge)
+		if err != nil {
+			return nil, err
+		}
+		weights = append(weights, weight)
+	}
+
+	if n.Threshold < 1 || n.Threshold > math.MaxUint32 {
+		return nil, fmt.Errorf("required-weight %d out of range", n.Threshold)
+	}
+
+	return cryptex.NewWeightedSSS(weights, uint32(n.Threshold), n.Comment), nil
+}
+
+// Edges for WeightedSSS
+func (n WeightedSSS) Edges() []string {
+	edges := make([]string, 0, len(n.EdgeSlice))
+	for _, edge := range n.EdgeSlice {
+		name, _, _ := parseWeightedEdge(edge)
+		edges = append(edges, name)
+	}
+	return edges
+}
+
+func parseWeightedEdge(edge string) (string, uint32, error) {
+	idx := strings.LastIndex(edge, ":")
+	if idx == -1 || !isDigits(edge[idx+1:]) {
+		return edge, 1, nil
+	}
+
+	weight, err := strconv.ParseUint(edge[idx+1:], 10, 8)
+	if err != nil || weight == 0 {
+		return edge[:idx], 0, fmt.Errorf("invalid weight for edge %q", edge)
+	}
+	return edge[:idx], uint32(weight), nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Threshold config
+type Threshold struct {
+	Comment   string   `vcrypt:"comment,optional"`
+	EdgeSlice []string `vcrypt:"edge,optional"`
+
+	N int `vcrypt:"max-shares"`
+	K int `vcrypt:"required-shares"`
+}
+
+// Cryptex for Threshold
+func (n Threshold) Cryptex() (cryptex.Cryptex, error) {
+	if n.K < 1 || n.K > n.N || n.N > cryptex.MaxThresholdShares {
+		return nil, cryptex.ErrThresholdParams
+	}
+	return cryptex.NewThreshold(uint32(n.N), uint32(n.K), n.Comment), nil
+}
+
+// Edges for Threshold
+func (n Threshold) Edges() []string { return n.EdgeSlice }
+
+// TimeLock config
+type TimeLock struct {
+	Comment   string   `vcrypt:"comment,optional"`
+	EdgeSlice []string `vcrypt:"edge,optional"`
+
+	// target time to open, calibrated on this machine, e.g. 72h
+	Duration string `vcrypt:"duration,optional"`
+
+	Iterations int `vcrypt:"iterations,optional"`
+	Bits       int `vcrypt:"bits,optional"`
+}
+
+// Cryptex for TimeLock
+func (n TimeLock) Cryptex() (cryptex.Cryptex, error) {
+	bits := uint32(cryptex.DefaultTimeLockBits)
+	if n.Bits != 0 {
+		if n.Bits < 512 || n.Bits > 16384 {
+			return nil, fmt.Errorf("timelock bits %d out of range", n.Bits)
+		}
+		bits = uint32(n.Bits)
+	}
+
+	switch {
+	case n.Duration != "" && n.Iterations != 0:
+		return nil, errors.New("timelock requires one of duration or iterations")
+	case n.Iterations != 0:
+		if n.Iterations < 1 {
+			return nil, fmt.Errorf("timelock iterations %d out of range", n.Iterations)
+		}
+		return cryptex.NewTimeLock(uint64(n.Iterations), bits, n.Comment), nil
+	case n.Duration != "":
+		d, err := time.ParseDuration(n.Duration)
+		if err != nil {
+			return nil, err
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("timelock duration %s out of range", d)
+		}
+
+		iterations, err := cryptex.CalibrateTimeLock(d, bits)
+		if err != nil {
+			return nil, err
+		}
+		if iterations < 1 {
+			iterations = 1
+		}
+		return cryptex.NewTimeLock(iterations, bits, n.Comment), nil
+	default:
+		return nil, errors.New("timelock requires one of duration or iterations")
+	}
+}
+
+// Edges for TimeLock
+func (n TimeLock) Edges() []string { return n.EdgeSlice }
+
 // XOR config
 type XOR struct {
 	Comment   string   `vcrypt:"comment,optional"`
@@ -149,6 +379,70 @@ func (n SecretBox) Cryptex() (cryptex.Cryptex, error) {
 // Edges for SecretBox
 func (n SecretBox) Edges() []string { return n.EdgeSlice }
 
+// KDFSecretBox config
+type KDFSecretBox struct {
+	Comment   string   `vcrypt:"comment,optional"`
+	EdgeSlice []string `vcrypt:"edge,optional"`
+
+	KDF string `vcrypt:"kdf,optional"`
+
+	// argon2id cost, memory is in KiB
+	Time    int `vcrypt:"time,optional"`
+	Memory  int `vcrypt:"memory,optional"`
+	Threads int `vcrypt:"threads,optional"`
+
+	// scrypt cost
+	LogN int `vcrypt:"log-n,optional"`
+	R    int `vcrypt:"r,optional"`
+	P    int `vcrypt:"p,optional"`
+}
+
+// Cryptex for KDFSecretBox
+func (n KDFSecretBox) Cryptex() (cryptex.Cryptex, error) {
+	for _, v := range []int{n.Time, n.Memory, n.Threads, n.LogN, n.R, n.P} {
+		if v < 0 || v > math.MaxUint32 {
+			return nil, errors.New("kdf-secretbox cost out of range")
+		}
+	}
+
+	switch n.KDF {
+	case "", "argon2id":
+		if n.LogN != 0 || n.R != 0 || n.P != 0 {
+			return nil, errors.New("argon2id kdf-secretbox does not support log-n, r, or p")
+		}
+
+		return cryptex.NewArgon2idSecretBox(
+			uint32(defaultInt(n.Time, 3)),
+			uint32(defaultInt(n.Memory, 64*1024)),
+			uint32(defaultInt(n.Threads, 4)),
+			n.Comment,
+		)
+	case "scrypt":
+		if n.Time != 0 || n.Memory != 0 || n.Threads != 0 {
+			return nil, errors.New("scrypt kdf-secretbox does not support time, memory, or threads")
+		}
+
+		return cryptex.NewScryptSecretBox(
+			uint32(defaultInt(n.LogN, 17)),
+			uint32(defaultInt(n.R, 8)),
+			uint32(defaultInt(n.P, 1)),
+			n.Comment,
+		)
+	default:
+		return nil, fmt.Errorf("unknown kdf %q, must be argon2id or scrypt", n.KDF)
+	}
+}
+
+// Edges for KDFSecretBox
+func (n KDFSecretBox) Edges() []string { return n.EdgeSlice }
+
+func defaultInt(v, def int) int {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
 // Box config
 type Box struct {
 	Comment   string   `vcrypt:"comment,optional"`
@@ -170,6 +464,52 @@ func (n Box) Cryptex() (cryptex.Cryptex, error) {
 // Edges for Box
 func (n Box) Edges() []string { return n.EdgeSlice }
 
+// HybridKEM config
+type HybridKEM struct {
+	Comment   string   `vcrypt:"comment,optional"`
+	EdgeSlice []string `vcrypt:"edge,optional"`
+
+	PublicKey string `vcrypt:"publickey"`
+}
+
+// Cryptex for HybridKEM
+func (n HybridKEM) Cryptex() (cryptex.Cryptex, error) {
+	pk, err := base64.StdEncoding.DecodeString(n.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(pk) != cryptex.HybridKEMPublicKeySize {
+		return nil, errors.New("invalid hybrid-kem publickey size")
+	}
+
+	return cryptex.NewHybridKEM(pk, n.Comment), nil
+}
+
+// Edges for HybridKEM
+func (n HybridKEM) Edges() []string { return n.EdgeSlice }
+
+// Age config
+type Age struct {
+	Comment   string   `vcrypt:"comment,optional"`
+	EdgeSlice []string `vcrypt:"edge,optional"`
+
+	Recipients []string `vcrypt:"recipient"`
+}
+
+// Cryptex for Age
+func (n Age) Cryptex() (cryptex.Cryptex, error) {
+	for _, rcpt := range n.Recipients {
+		if _, err := age.ParseX25519Recipient(rcpt); err != nil {
+			return nil, err
+		}
+	}
+
+	return cryptex.NewAge(n.Recipients, n.Comment), nil
+}
+
+// Edges for Age
+func (n Age) Edges() []string { return n.EdgeSlice }
+
 // RSA config
 type RSA struct {
 	Comment   string   `vcrypt:"comment,optional"`
@@ -273,6 +613,55 @@ func (n Demux) Cryptex() (cryptex.Cryptex, error) {
 // Edges for Demux
 func (n Demux) Edges() []string { return n.EdgeSlice }
 
+// Derive config
+type Derive struct {
+	Comment   string   `vcrypt:"comment,optional"`
+	EdgeSlice []string `vcrypt:"edge,optional"`
+
+	// one label per parent edge. Labels are bound to parents in the sorted
+	// order of the parent node IDs.
+	Labels []string `vcrypt:"label"`
+}
+
+// Cryptex for Derive
+func (n Derive) Cryptex() (cryptex.Cryptex, error) {
+	return cryptex.NewDerive(n.Labels, n.Comment)
+}
+
+// Edges for Derive
+func (n Derive) Edges() []string { return n.EdgeSlice }
+
+// SSH config
+type SSH struct {
+	Comment   string   `vcrypt:"comment,optional"`
+	EdgeSlice []string `vcrypt:"edge,optional"`
+
+	SSHKey string `vcrypt:"ssh-key"`
+}
+
+// Cryptex for SSH
+func (n SSH) Cryptex() (cryptex.Cryptex, error) {
+	pk, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(n.SSHKey))
+	if err != nil {
+		return nil, err
+	}
+
+	switch pk.Type() {
+	case ssh.KeyAlgoED25519, ssh.KeyAlgoECDSA256, ssh.KeyAlgoECDSA384, ssh.KeyAlgoECDSA521:
+	default:
+		return nil, fmt.Errorf("unsupported ssh-key type %q for ssh cryptex", pk.Type())
+	}
+
+	if n.Comment != "" {
+		comment = n.Comment
+	}
+
+	return cryptex.NewSSH(pk.Marshal(), comment), nil
+}
+
+// Edges for SSH
+func (n SSH) Edges() []string { return n.EdgeSlice }
+
 // Password config
 type Password struct {
 	Comment string `vcrypt:"comment,optional"`
@@ -322,7 +711,7 @@ func (n SSHKey) Secret() (secret.Secret, error) {
 		return nil, errors.New("ssh secret requires either authorized-key or fingerprint")
 	}
 
-	pk, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(n.AuthorizedKey))
+	fingerprint, comment, err := authorizedKeyFingerprint(n.AuthorizedKey)
 	if err != nil {
 		return nil, err
 	}
@@ -331,9 +720,234 @@ func (n SSHKey) Secret() (secret.Secret, error) {
 		comment = n.Comment
 	}
 
-	sum := sha256.Sum256(pk.Marshal())
-	digest := base64.StdEncoding.EncodeToString(sum[:])
-	fingerprint := "SHA256:" + strings.TrimRight(digest, "=")
-
 	return secret.NewSSHKey(fingerprint, comment)
 }
+
+// SSHAgent config
+type SSHAgent struct {
+	Comment string `vcrypt:"comment,optional"`
+
+	AuthorizedKey string `vcrypt:"authorized-key,optional"`
+	Fingerprint   string `vcrypt:"fingerprint,optional"`
+}
+
+// Secret for SSHAgent
+func (n SSHAgent) Secret() (secret.Secret, error) {
+	if n.Fingerprint != "" {
+		return secret.NewSSHAgent(n.Fingerprint, n.Comment)
+	}
+
+	if n.AuthorizedKey == "" {
+		return nil, errors.New("ssh-agent secret requires either authorized-key or fingerprint")
+	}
+
+	fingerprint, comment, err := authorizedKeyFingerprint(n.AuthorizedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Comment != "" {
+		comment = n.Comment
+	}
+
+	return secret.NewSSHAgent(fingerprint, comment)
+}
+
+// X25519Key config
+type X25519Key struct {
+	Comment string `vcrypt:"comment,optional"`
+
+	PublicKey string `vcrypt:"publickey"`
+}
+
+// Secret for X25519Key
+func (n X25519Key) Secret() (secret.Secret, error) {
+	pk, err := base64.StdEncoding.DecodeString(n.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return secret.NewX25519Key(pk, n.Comment)
+}
+
+// HybridKEMKey config
+type HybridKEMKey struct {
+	Comment string `vcrypt:"comment,optional"`
+
+	PublicKey string `vcrypt:"publickey"`
+}
+
+// Secret for HybridKEMKey
+func (n HybridKEMKey) Secret() (secret.Secret, error) {
+	pk, err := base64.StdEncoding.DecodeString(n.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return secret.NewHybridKEMKey(pk, n.Comment)
+}
+
+// AgeIdentity config
+type AgeIdentity struct {
+	Comment string `vcrypt:"comment,optional"`
+
+	Recipients []string `vcrypt:"recipient,optional"`
+}
+
+// Secret for AgeIdentity
+func (n AgeIdentity) Secret() (secret.Secret, error) {
+	return secret.NewAgeIdentity(n.Recipients, n.Comment)
+}
+
+// PKCS11Key config
+type PKCS11Key struct {
+	Comment string `vcrypt:"comment,optional"`
+
+	// token label, or slot id when the token label is empty
+	Token string `vcrypt:"token,optional"`
+	Slot  int    `vcrypt:"slot,optional"`
+
+	Label   string `vcrypt:"label"`
+	PKIXKey string `vcrypt:"pkix-key"`
+}
+
+// Secret for PKCS11Key
+func (n PKCS11Key) Secret() (secret.Secret, error) {
+	if n.Slot < 0 {
+		return nil, fmt.Errorf("pkcs11-key slot %d out of range", n.Slot)
+	}
+
+	p, _ := pem.Decode([]byte(n.PKIXKey))
+	if p == nil {
+		return nil, errors.New("invalid PKIX key, must be PEM encoded")
+	}
+
+	return secret.NewPKCS11Key(n.Token, uint64(n.Slot), n.Label, p.Bytes, n.Comment)
+}
+
+// Seal config. Key paths may start with ~/ for the home directory.
+type Seal struct {
+	Ed25519Key string `vcrypt:"ed25519-key,optional"`
+	SSHKey     string `vcrypt:"ssh-key,optional"`
+}
+
+// Sealer for Seal
+func (n Seal) Sealer() (Sealer, error) {
+	if n.SSHKey != "" {
+		data, err := readKeyFile(n.SSHKey)
+		if err != nil {
+			return nil, err
+		}
+
+		signer, err := ssh.ParsePrivateKey(data)
+		if err != nil {
+			return nil, err
+		}
+
+		return seal.NewSSHSigSealer(signer), nil
+	}
+
+	if n.Ed25519Key == "" {
+		return nil, errors.New("seal requires either ed25519-key or ssh-key")
+	}
+
+	data, err := readKeyFile(n.Ed25519Key)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := seal.ParseEd25519Key(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return seal.NewEd25519Sealer(key), nil
+}
+
+// readKeyFile reads the key file at path, expanding a leading ~/ to the home
+// directory.
+func readKeyFile(path string) ([]byte, error) {
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, path[2:])
+	}
+	return ioutil.ReadFile(path)
+}
+
+// Policy config
+type Policy struct {
+	Threshold int `vcrypt:"threshold"`
+
+	Signers map[string]Signer `vcrypt:"signer,section"`
+}
+
+// Signer config. Fingerprints must be full hex OpenPGP fingerprints or SSH
+// SHA256 fingerprints; short OpenPGP key IDs are rejected.
+type Signer struct {
+	Fingerprints   []string `vcrypt:"fingerprint,optional"`
+	AuthorizedKeys []string `vcrypt:"authorized-key,optional"`
+
+	// KeyIDs is the former name of Fingerprints & is rejected.
+	KeyIDs []string `vcrypt:"keyid,optional"`
+}
+
+// Policy builds the seal policy. Signers are ordered by name.
+func (p Policy) Policy() (*seal.Policy, error) {
+	names := make([]string, 0, len(p.Signers))
+	for name := range p.Signers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pol := &seal.Policy{
+		Threshold: p.Threshold,
+		Signers:   make([]seal.Signer, 0, len(names)),
+	}
+
+	for _, name := range names {
+		signer, err := p.Signers[name].Signer(name)
+		if err != nil {
+			return nil, err
+		}
+		pol.Signers = append(pol.Signers, signer)
+	}
+
+	if pol.Threshold < 1 || pol.Threshold > len(pol.Signers) {
+		return nil, fmt.Errorf("seal policy threshold must be between 1 and %d", len(pol.Signers))
+	}
+
+	return pol, nil
+}
+
+// Signer for the signer section named name.
+func (n Signer) Signer(name string) (seal.Signer, error) {
+	if len(n.KeyIDs) > 0 {
+		return seal.Signer{}, fmt.Errorf("signer %q: keyid is not supported, use fingerprint", name)
+	}
+
+	ids := append([]string{}, n.Fingerprints...)
+
+	for i, id := range ids {
+		var err error
+		if ids[i], err = seal.ParseIdentity(id); err != nil {
+			return seal.Signer{}, fmt.Errorf("signer %q: %s", name, err)
+		}
+	}
+
+	for _, key := range n.AuthorizedKeys {
+		fingerprint, _, err := authorizedKeyFingerprint(key)
+		if err != nil {
+			return seal.Signer{}, err
+		}
+		ids = append(ids, fingerprint)
+	}
+
+	if len(ids) == 0 {
+		return seal.Signer{}, fmt.Errorf("signer %q requires a fingerprint or authorized-key", name)
+	}
+
+	return seal.Signer{Name: name, IDs: ids}, nil
+}
@@ -1,11 +1,21 @@
 package config
 
 import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
 	"testing"
 
+	"github.com/vcrypt/vcrypt/cryptex"
 	"github.com/vcrypt/vcrypt/internal/test"
+	"github.com/vcrypt/vcrypt/seal"
 	"github.com/vcrypt/vcrypt/secret"
+	"golang.org/x/crypto/ssh"
 )
 
 func TestSSHKey(t *testing.T) {
@@ -67,6 +77,119 @@ func TestSSHKey(t *testing.T) {
 	}
 }
 
+func TestSSHAgent(t *testing.T) {
+	tests := []struct {
+		config SSHAgent
+
+		comment string
+		err     error
+	}{
+		// authorized_keys formatted public key & comment
+		{
+			config: SSHAgent{
+				AuthorizedKey: sshEd25519Key,
+			},
+			comment: "alice",
+		},
+		// fingerprint of public key
+		{
+			config: SSHAgent{
+				Comment:     "alice's agent key",
+				Fingerprint: "SHA256:Wb3tN6pb64q/c8jyBzbpa84nJweyEvws1XPrdt+MBmk",
+			},
+			comment: "alice's agent key",
+		},
+		// errors
+		{
+			config: SSHAgent{},
+			err:    errors.New("ssh-agent secret requires either authorized-key or fingerprint"),
+		},
+	}
+
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(sshEd25519Key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		sec, err := test.config.Secret()
+		if err != nil {
+			if test.err != nil {
+				if err.Error() != test.err.Error() {
+					t.Errorf("want error %q, got %q", test.err.Error(), err.Error())
+				}
+			} else {
+				t.Error(err)
+			}
+			continue
+		}
+
+		if test.comment != sec.Comment() {
+			t.Errorf("want comment %q, got %q", test.comment, sec.Comment())
+		}
+		if !sec.(*secret.SSHAgent).Match(pub) {
+			t.Errorf("want ssh-agent secret to match %q", sshEd25519Key)
+		}
+	}
+}
+
+func TestSSH(t *testing.T) {
+	tests := []struct {
+		config SSH
+
+		comment string
+		err     error
+	}{
+		// ssh-ed25519 key & comment
+		{
+			config: SSH{
+				SSHKey: sshEd25519Key,
+			},
+			comment: "alice",
+		},
+		// config comment overrides key comment
+		{
+			config: SSH{
+				Comment: "alice's ed25519 key",
+				SSHKey:  sshEd25519Key,
+			},
+			comment: "alice's ed25519 key",
+		},
+		// errors
+		{
+			config: SSH{
+				SSHKey: test.Users["alice"].SSHKey.Public,
+			},
+			err: errors.New(`unsupported ssh-key type "ssh-rsa" for ssh cryptex`),
+		},
+	}
+
+	for _, test := range tests {
+		cptx, err := test.config.Cryptex()
+		if err != nil {
+			if test.err != nil {
+				if err.Error() != test.err.Error() {
+					t.Errorf("want error %q, got %q", test.err.Error(), err.Error())
+				}
+			} else {
+				t.Error(err)
+			}
+			continue
+		}
+
+		if test.comment != cptx.Comment() {
+			t.Errorf("want comment %q, got %q", test.comment, cptx.Comment())
+		}
+	}
+
+	rsa := RSA{SSHKey: sshEd25519Key}
+	if _, err := rsa.Cryptex(); err == nil {
+		t.Errorf("want rsa cryptex error for ssh-ed25519 key, got nil")
+	}
+}
+
+var sshEd25519Key = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDV2YnBai/vNXYgsFZQiR+oae09KR3blbTtxdzsZlxjy alice"
+
 func mustSSHKey(fingerprint, comment string) *secret.SSHKey {
 	sshKey, err := secret.NewSSHKey(fingerprint, comment)
 	if err != nil {
@@ -74,3 +197,217 @@ func mustSSHKey(fingerprint, comment string) *secret.SSHKey {
 	}
 	return sshKey
 }
+
+func TestSeal(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	seed := bytes.Repeat([]byte{0x42}, ed25519.SeedSize)
+	data := []byte(base64.StdEncoding.EncodeToString(seed))
+	if err := ioutil.WriteFile(filepath.Join(home, "seal.key"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	slr, err := Seal{Ed25519Key: "~/seal.key"}.Sealer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := slr.Seal([]byte("plan data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	if got := s.(*seal.Ed25519).PublicKey; !bytes.Equal(want, got) {
+		t.Errorf("want seal public key %x, got %x", want, got)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	data := `
+threshold = 2
+
+[signer "bob"]
+fingerprint = 37D4 A8F1 A26C 3C4E 8F6E  0B3F AE9D 3A7A FAED DE7A
+
+[signer "alice"]
+authorized-key = "` + sshEd25519Key + `"
+fingerprint = SHA256:Wb3tN6pb64q/c8jyBzbpa84nJweyEvws1XPrdt+MBmk
+`
+
+	var config Policy
+	if err := Unmarshal([]byte(data), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	pol, err := config.Policy()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 2, pol.Threshold; want != got {
+		t.Errorf("want threshold %d, got %d", want, got)
+	}
+
+	fp, _, err := authorizedKeyFingerprint(sshEd25519Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []seal.Signer{
+		{Name: "alice", IDs: []string{"SHA256:Wb3tN6pb64q/c8jyBzbpa84nJweyEvws1XPrdt+MBmk", fp}},
+		{Name: "bob", IDs: []string{"37D4A8F1A26C3C4E8F6E0B3FAE9D3A7AFAEDDE7A"}},
+	}
+	if !reflect.DeepEqual(want, pol.Signers) {
+		t.Errorf("want signers %v, got %v", want, pol.Signers)
+	}
+
+	config.Threshold = 3
+	if _, err := config.Policy(); err == nil {
+		t.Errorf("want threshold error for 3 of 2 signers, got nil")
+	}
+
+	config.Threshold = 2
+	for _, id := range []string{"FAEDDE7A", "AE9D3A7AFAEDDE7A"} {
+		config.Signers["bob"] = Signer{Fingerprints: []string{id}}
+		_, err := config.Policy()
+		if want := fmt.Sprintf(`signer "bob": %q is an OpenPGP key ID, not a full fingerprint`, id); err == nil || err.Error() != want {
+			t.Errorf("want error %q, got %v", want, err)
+		}
+	}
+
+	config.Signers["bob"] = Signer{KeyIDs: []string{"37D4A8F1A26C3C4E8F6E0B3FAE9D3A7AFAEDDE7A"}}
+	if _, err := config.Policy(); err == nil {
+		t.Errorf("want error for keyid signer, got nil")
+	}
+}
+
+func TestX25519Key(t *testing.T) {
+	config := X25519Key{
+		Comment:   "alice's box key",
+		PublicKey: "xcleKkTeHA6IjTxf+R5Jxl3yJR3qApghcLRpYqpUnhY=",
+	}
+
+	sec, err := config.Secret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "alice's box key", sec.Comment(); want != got {
+		t.Errorf("want comment %q, got %q", want, got)
+	}
+
+	config.PublicKey = "c2hvcnQ="
+	if _, err := config.Secret(); err == nil {
+		t.Errorf("want error for short public key, got nil")
+	}
+}
+
+func TestAge(t *testing.T) {
+	config := Age{
+		Comment:    "backup recipients",
+		EdgeSlice:  []string{"age file", "age identity"},
+		Recipients: []string{"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"},
+	}
+
+	cptx, err := config.Cryptex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "backup recipients", cptx.Comment(); want != got {
+		t.Errorf("want comment %q, got %q", want, got)
+	}
+
+	config.Recipients = []string{"age1invalid"}
+	if _, err := config.Cryptex(); err == nil {
+		t.Errorf("want error for invalid recipient, got nil")
+	}
+}
+
+func TestPKCS11Key(t *testing.T) {
+	config := PKCS11Key{
+		Comment: "hsm root key",
+		Token:   "vault token",
+		Label:   "root",
+		PKIXKey: `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEYIEhsFTaFwExoX9ShfEZC/K1OfB2
+MbyQW5uGwHd8jFOIzj2wbIMWl3HB4IVUJ+7VkfdRj+4kUvS7EyNgVQ97qw==
+-----END PUBLIC KEY-----`,
+	}
+
+	sec, err := config.Secret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "root", sec.(*secret.PKCS11Key).Label; want != got {
+		t.Errorf("want label %q, got %q", want, got)
+	}
+
+	config.PKIXKey = "not a key"
+	if _, err := config.Secret(); err == nil {
+		t.Errorf("want error for invalid pkix-key, got nil")
+	}
+}
+
+func TestWeightedSSS(t *testing.T) {
+	config := WeightedSSS{
+		Comment:   "votes",
+		EdgeSlice: []string{"ciso:2", "engineer 1", "engineer 2:1", "ops:east key", "vault:8200:1"},
+		Threshold: 3,
+	}
+
+	if want, got := []string{"ciso", "engineer 1", "engineer 2", "ops:east key", "vault:8200"}, config.Edges(); !reflect.DeepEqual(want, got) {
+		t.Errorf("want edges %q, got %q", want, got)
+	}
+
+	cptx, err := config.Cryptex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := []uint32{2, 1, 1, 1, 1}, cptx.(*cryptex.WeightedSSS).Weights; !reflect.DeepEqual(want, got) {
+		t.Errorf("want weights %v, got %v", want, got)
+	}
+
+	config.EdgeSlice[0] = "ciso:0"
+	if _, err := config.Cryptex(); err == nil {
+		t.Errorf("want error for zero weight, got nil")
+	}
+
+	config.EdgeSlice[0] = "ciso:256"
+	if _, err := config.Cryptex(); err == nil {
+		t.Errorf("want error for weight above 255, got nil")
+	}
+}
+
+func TestTimeLock(t *testing.T) {
+	config := TimeLock{
+		Comment:    "embargo",
+		Iterations: 1000,
+		Bits:       1024,
+	}
+
+	cptx, err := config.Cryptex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := uint64(1000), cptx.(*cryptex.TimeLock).Iterations; want != got {
+		t.Errorf("want %d iterations, got %d", want, got)
+	}
+
+	config.Iterations, config.Duration = 0, "1h"
+	if cptx, err = config.Cryptex(); err != nil {
+		t.Fatal(err)
+	}
+	if cptx.(*cryptex.TimeLock).Iterations == 0 {
+		t.Errorf("want calibrated iterations, got 0")
+	}
+
+	config.Iterations = 1000
+	if _, err := config.Cryptex(); err == nil {
+		t.Errorf("want error for both duration and iterations, got nil")
+	}
+
+	config.Iterations, config.Duration = 0, "soon"
+	if _, err := config.Cryptex(); err == nil {
+		t.Errorf("want error for invalid duration, got nil")
+	}
+}
@@ -3,8 +3,10 @@ package config
 import (
 	"crypto"
 	"crypto/rsa"
-	"math/big"
-	"reflect"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
 
 	"golang.org/x/crypto/ssh"
 )
@@ -15,14 +17,22 @@ func parseAuthorizedKey(in string) (key crypto.PublicKey, comment string, err er
 		return nil, comment, err
 	}
 
-	val := reflect.ValueOf(pub).Elem()
-	n := val.FieldByName("N").Interface().(*big.Int)
-	e := val.FieldByName("E").Interface().(int)
+	if cpub, ok := pub.(ssh.CryptoPublicKey); ok {
+		if key, ok := cpub.CryptoPublicKey().(*rsa.PublicKey); ok {
+			return key, comment, nil
+		}
+	}
+
+	return nil, comment, fmt.Errorf("unsupported ssh-key type %q for rsa cryptex", pub.Type())
+}
 
-	key = &rsa.PublicKey{
-		N: n,
-		E: e,
+func authorizedKeyFingerprint(in string) (fingerprint, comment string, err error) {
+	pub, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(in))
+	if err != nil {
+		return "", comment, err
 	}
 
-	return
+	sum := sha256.Sum256(pub.Marshal())
+	digest := base64.StdEncoding.EncodeToString(sum[:])
+	return "SHA256:" + strings.TrimRight(digest, "="), comment, nil
 }
@@ -0,0 +1,100 @@
+package cryptex
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+
+	"filippo.io/age"
+)
+
+// NewAge constructs a new Age for the age X25519 recipients, in the
+// "age1..." Bech32 encoding.
+func NewAge(recipients []string, comment string) *Age {
+	return &Age{
+		Recipients: recipients,
+		comment:    comment,
+	}
+}
+
+// Comment string
+func (c *Age) Comment() string {
+	return c.comment
+}
+
+// Close encrypts the secret to the Recipients. The input data is a standard
+// binary age file that any of the recipient identities can decrypt with the
+// age tool.
+func (c *Age) Close(inputs, secrets [][]byte) error {
+	rcpts, err := c.recipients()
+	if err != nil {
+		return err
+	}
+	if len(inputs) != 2 {
+		return errors.New("Age requires 2 inputs")
+	}
+	if len(secrets) != 1 {
+		return errors.New("Age supports 1 secret")
+	}
+
+	buf := &bytes.Buffer{}
+	w, err := age.Encrypt(buf, rcpts...)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(secrets[0]); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	inputs[0] = buf.Bytes()
+	inputs[1] = nil
+	return nil
+}
+
+// Open decrypts the secret from the age file & identity portions of the input
+// data. The identities are newline separated "AGE-SECRET-KEY-1..." strings.
+func (c *Age) Open(secrets, inputs [][]byte) error {
+	if _, err := c.recipients(); err != nil {
+		return err
+	}
+	if len(inputs) != 2 {
+		return errors.New("len(inputs) must be 2")
+	}
+
+	ids, err := age.ParseIdentities(bytes.NewReader(inputs[1]))
+	if err != nil {
+		return err
+	}
+
+	r, err := age.Decrypt(bytes.NewReader(inputs[0]), ids...)
+	if err != nil {
+		return err
+	}
+
+	secret, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	secrets[0] = secret
+	return nil
+}
+
+func (c *Age) recipients() ([]age.Recipient, error) {
+	if len(c.Recipients) == 0 {
+		return nil, errors.New("Recipients missing")
+	}
+
+	rcpts := make([]age.Recipient, 0, len(c.Recipients))
+	for _, s := range c.Recipients {
+		rcpt, err := age.ParseX25519Recipient(s)
+		if err != nil {
+			return nil, err
+		}
+		rcpts = append(rcpts, rcpt)
+	}
+	return rcpts, nil
+}
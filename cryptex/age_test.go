@@ -0,0 +1,92 @@
+package cryptex
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func TestAge(t *testing.T) {
+	want := [][]byte{[]byte("super secret password")}
+
+	alice, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eve, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cptx := NewAge([]string{alice.Recipient().String(), bob.Recipient().String()}, "Age cryptex")
+
+	inputs := make([][]byte, 2)
+	if err := cptx.Close(inputs, want); err != nil {
+		t.Fatal(err)
+	}
+	if inputs[1] != nil {
+		t.Errorf("want inputs[1] to be nil, got %v", inputs[1])
+	}
+
+	// the input data is a standard age file
+	r, err := age.Decrypt(bytes.NewReader(inputs[0]), bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(want[0], data) {
+		t.Errorf("want age file plaintext %q, got %q", want[0], data)
+	}
+
+	for _, id := range []*age.X25519Identity{alice, bob} {
+		got := make([][]byte, len(want))
+		if err := cptx.Open(got, [][]byte{inputs[0], []byte(eve.String() + "\n" + id.String())}); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("want secret %q, got %q", want, got)
+		}
+	}
+
+	got := make([][]byte, len(want))
+	if err := cptx.Open(got, [][]byte{inputs[0], []byte(eve.String())}); err == nil {
+		t.Errorf("Age cryptex opened with wrong identity")
+	}
+
+	if err := NewAge([]string{"age1invalid"}, "").Close(make([][]byte, 2), want); err == nil {
+		t.Errorf("want error for invalid recipient, got nil")
+	}
+}
+
+func TestRoundTripAge(t *testing.T) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := NewAge([]string{id.Recipient().String()}, "Age cryptex")
+
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got.(*Age)) {
+		t.Errorf("want Age cryptex %v, got %v", want, got)
+	}
+}
@@ -1,6 +1,9 @@
 package cryptex
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 // Cryptex lock intermediate secrets.
 type Cryptex interface {
@@ -20,6 +23,14 @@ type Cryptex interface {
 	Unmarshal(data []byte) error
 }
 
+// ContextOpener is implemented by a Cryptex with a slow Open, such as a
+// TimeLock, that can be cancelled & reports its progress.
+type ContextOpener interface {
+	// OpenContext is Open with cancellation by ctx. The optional progress
+	// func is called periodically with the work done of the total.
+	OpenContext(ctx context.Context, progress func(done, total uint64), secrets, inputs [][]byte) error
+}
+
 // Wrap returns an intermediate form of the cryptex for marshalling.
 func Wrap(cptx Cryptex) (*Envelope, error) {
 	env := &Envelope{}
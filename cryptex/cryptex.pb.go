@@ -15,6 +15,15 @@
 		cryptex/openpgp.proto
 		cryptex/mux.proto
 		cryptex/demux.proto
+		cryptex/ssh.proto
+		cryptex/kdfsecretbox.proto
+		cryptex/hybridkem.proto
+		cryptex/age.proto
+		cryptex/vss.proto
+		cryptex/weightedsss.proto
+		cryptex/threshold.proto
+		cryptex/timelock.proto
+		cryptex/derive.proto
 
 	It has these top-level messages:
 		Envelope
@@ -32,14 +41,23 @@ import fmt "fmt"
 var _ = proto.Marshal
 
 type Envelope struct {
-	SSS       *SSS       `protobuf:"bytes,1,opt,name=sss" json:"sss,omitempty"`
-	XOR       *XOR       `protobuf:"bytes,2,opt,name=xor" json:"xor,omitempty"`
-	SecretBox *SecretBox `protobuf:"bytes,3,opt,name=secretbox" json:"secretbox,omitempty"`
-	Box       *Box       `protobuf:"bytes,4,opt,name=box" json:"box,omitempty"`
-	RSA       *RSA       `protobuf:"bytes,5,opt,name=rsa" json:"rsa,omitempty"`
-	OpenPGP   *OpenPGP   `protobuf:"bytes,6,opt,name=openpgp" json:"openpgp,omitempty"`
-	Mux       *Mux       `protobuf:"bytes,7,opt,name=mux" json:"mux,omitempty"`
-	Demux     *Demux     `protobuf:"bytes,8,opt,name=demux" json:"demux,omitempty"`
+	SSS          *SSS          `protobuf:"bytes,1,opt,name=sss" json:"sss,omitempty"`
+	XOR          *XOR          `protobuf:"bytes,2,opt,name=xor" json:"xor,omitempty"`
+	SecretBox    *SecretBox    `protobuf:"bytes,3,opt,name=secretbox" json:"secretbox,omitempty"`
+	Box          *Box          `protobuf:"bytes,4,opt,name=box" json:"box,omitempty"`
+	RSA          *RSA          `protobuf:"bytes,5,opt,name=rsa" json:"rsa,omitempty"`
+	OpenPGP      *OpenPGP      `protobuf:"bytes,6,opt,name=openpgp" json:"openpgp,omitempty"`
+	Mux          *Mux          `protobuf:"bytes,7,opt,name=mux" json:"mux,omitempty"`
+	Demux        *Demux        `protobuf:"bytes,8,opt,name=demux" json:"demux,omitempty"`
+	SSH          *SSH          `protobuf:"bytes,9,opt,name=ssh" json:"ssh,omitempty"`
+	KDFSecretBox *KDFSecretBox `protobuf:"bytes,10,opt,name=kdfsecretbox" json:"kdfsecretbox,omitempty"`
+	HybridKEM    *HybridKEM    `protobuf:"bytes,11,opt,name=hybridkem" json:"hybridkem,omitempty"`
+	Age          *Age          `protobuf:"bytes,12,opt,name=age" json:"age,omitempty"`
+	VSS          *VSS          `protobuf:"bytes,13,opt,name=vss" json:"vss,omitempty"`
+	WeightedSSS  *WeightedSSS  `protobuf:"bytes,14,opt,name=weightedsss" json:"weightedsss,omitempty"`
+	Threshold    *Threshold    `protobuf:"bytes,15,opt,name=threshold" json:"threshold,omitempty"`
+	Timelock     *TimeLock     `protobuf:"bytes,16,opt,name=timelock" json:"timelock,omitempty"`
+	Derive       *Derive       `protobuf:"bytes,17,opt,name=derive" json:"derive,omitempty"`
 }
 
 func (m *Envelope) Reset()         { *m = Envelope{} }
@@ -102,6 +120,69 @@ func (m *Envelope) GetDemux() *Demux {
 	return nil
 }
 
+func (m *Envelope) GetSSH() *SSH {
+	if m != nil {
+		return m.SSH
+	}
+	return nil
+}
+
+func (m *Envelope) GetKDFSecretBox() *KDFSecretBox {
+	if m != nil {
+		return m.KDFSecretBox
+	}
+	return nil
+}
+
+func (m *Envelope) GetHybridKEM() *HybridKEM {
+	if m != nil {
+		return m.HybridKEM
+	}
+	return nil
+}
+
+func (m *Envelope) GetAge() *Age {
+	if m != nil {
+		return m.Age
+	}
+	return nil
+}
+
+func (m *Envelope) GetVSS() *VSS {
+	if m != nil {
+		return m.VSS
+	}
+	return nil
+}
+
+func (m *Envelope) GetWeightedSSS() *WeightedSSS {
+	if m != nil {
+		return m.WeightedSSS
+	}
+	return nil
+}
+
+func (m *Envelope) GetThreshold() *Threshold {
+	if m != nil {
+		return m.Threshold
+	}
+	return nil
+}
+
+func (m *Envelope) GetTimelock() *TimeLock {
+	if m != nil {
+		return m.Timelock
+	}
+	return nil
+}
+
+func (m *Envelope) GetDerive() *Derive {
+	if m != nil {
+		return m.Derive
+	}
+	return nil
+}
+
 func (m *Envelope) Marshal() (data []byte, err error) {
 	size := m.Size()
 	data = make([]byte, size)
@@ -197,6 +278,100 @@ func (m *Envelope) MarshalTo(data []byte) (int, error) {
 		}
 		i += n8
 	}
+	if m.SSH != nil {
+		data[i] = 0x4a
+		i++
+		i = encodeVarintCryptex(data, i, uint64(m.SSH.Size()))
+		n9, err := m.SSH.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
+	}
+	if m.KDFSecretBox != nil {
+		data[i] = 0x52
+		i++
+		i = encodeVarintCryptex(data, i, uint64(m.KDFSecretBox.Size()))
+		n10, err := m.KDFSecretBox.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	if m.HybridKEM != nil {
+		data[i] = 0x5a
+		i++
+		i = encodeVarintCryptex(data, i, uint64(m.HybridKEM.Size()))
+		n11, err := m.HybridKEM.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	if m.Age != nil {
+		data[i] = 0x62
+		i++
+		i = encodeVarintCryptex(data, i, uint64(m.Age.Size()))
+		n12, err := m.Age.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n12
+	}
+	if m.VSS != nil {
+		data[i] = 0x6a
+		i++
+		i = encodeVarintCryptex(data, i, uint64(m.VSS.Size()))
+		n13, err := m.VSS.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n13
+	}
+	if m.WeightedSSS != nil {
+		data[i] = 0x72
+		i++
+		i = encodeVarintCryptex(data, i, uint64(m.WeightedSSS.Size()))
+		n14, err := m.WeightedSSS.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
+	}
+	if m.Threshold != nil {
+		data[i] = 0x7a
+		i++
+		i = encodeVarintCryptex(data, i, uint64(m.Threshold.Size()))
+		n15, err := m.Threshold.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n15
+	}
+	if m.Timelock != nil {
+		data[i] = 0x82
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintCryptex(data, i, uint64(m.Timelock.Size()))
+		n16, err := m.Timelock.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n16
+	}
+	if m.Derive != nil {
+		data[i] = 0x8a
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintCryptex(data, i, uint64(m.Derive.Size()))
+		n17, err := m.Derive.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n17
+	}
 	return i, nil
 }
 
@@ -262,6 +437,42 @@ func (m *Envelope) Size() (n int) {
 		l = m.Demux.Size()
 		n += 1 + l + sovCryptex(uint64(l))
 	}
+	if m.SSH != nil {
+		l = m.SSH.Size()
+		n += 1 + l + sovCryptex(uint64(l))
+	}
+	if m.KDFSecretBox != nil {
+		l = m.KDFSecretBox.Size()
+		n += 1 + l + sovCryptex(uint64(l))
+	}
+	if m.HybridKEM != nil {
+		l = m.HybridKEM.Size()
+		n += 1 + l + sovCryptex(uint64(l))
+	}
+	if m.Age != nil {
+		l = m.Age.Size()
+		n += 1 + l + sovCryptex(uint64(l))
+	}
+	if m.VSS != nil {
+		l = m.VSS.Size()
+		n += 1 + l + sovCryptex(uint64(l))
+	}
+	if m.WeightedSSS != nil {
+		l = m.WeightedSSS.Size()
+		n += 1 + l + sovCryptex(uint64(l))
+	}
+	if m.Threshold != nil {
+		l = m.Threshold.Size()
+		n += 1 + l + sovCryptex(uint64(l))
+	}
+	if m.Timelock != nil {
+		l = m.Timelock.Size()
+		n += 2 + l + sovCryptex(uint64(l))
+	}
+	if m.Derive != nil {
+		l = m.Derive.Size()
+		n += 2 + l + sovCryptex(uint64(l))
+	}
 	return n
 }
 
@@ -303,6 +514,33 @@ func (this *Envelope) GetValue() interface{} {
 	if this.Demux != nil {
 		return this.Demux
 	}
+	if this.SSH != nil {
+		return this.SSH
+	}
+	if this.KDFSecretBox != nil {
+		return this.KDFSecretBox
+	}
+	if this.HybridKEM != nil {
+		return this.HybridKEM
+	}
+	if this.Age != nil {
+		return this.Age
+	}
+	if this.VSS != nil {
+		return this.VSS
+	}
+	if this.WeightedSSS != nil {
+		return this.WeightedSSS
+	}
+	if this.Threshold != nil {
+		return this.Threshold
+	}
+	if this.Timelock != nil {
+		return this.Timelock
+	}
+	if this.Derive != nil {
+		return this.Derive
+	}
 	return nil
 }
 
@@ -324,6 +562,24 @@ func (this *Envelope) SetValue(value interface{}) bool {
 		this.Mux = vt
 	case *Demux:
 		this.Demux = vt
+	case *SSH:
+		this.SSH = vt
+	case *KDFSecretBox:
+		this.KDFSecretBox = vt
+	case *HybridKEM:
+		this.HybridKEM = vt
+	case *Age:
+		this.Age = vt
+	case *VSS:
+		this.VSS = vt
+	case *WeightedSSS:
+		this.WeightedSSS = vt
+	case *Threshold:
+		this.Threshold = vt
+	case *TimeLock:
+		this.Timelock = vt
+	case *Derive:
+		this.Derive = vt
 	default:
 		return false
 	}
@@ -588,6 +844,276 @@ func (m *Envelope) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SSH", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthCryptex
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SSH == nil {
+				m.SSH = &SSH{}
+			}
+			if err := m.SSH.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KDFSecretBox", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthCryptex
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.KDFSecretBox == nil {
+				m.KDFSecretBox = &KDFSecretBox{}
+			}
+			if err := m.KDFSecretBox.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HybridKEM", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthCryptex
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.HybridKEM == nil {
+				m.HybridKEM = &HybridKEM{}
+			}
+			if err := m.HybridKEM.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Age", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthCryptex
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Age == nil {
+				m.Age = &Age{}
+			}
+			if err := m.Age.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VSS", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthCryptex
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.VSS == nil {
+				m.VSS = &VSS{}
+			}
+			if err := m.VSS.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WeightedSSS", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthCryptex
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.WeightedSSS == nil {
+				m.WeightedSSS = &WeightedSSS{}
+			}
+			if err := m.WeightedSSS.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Threshold", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthCryptex
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Threshold == nil {
+				m.Threshold = &Threshold{}
+			}
+			if err := m.Threshold.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timelock", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthCryptex
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Timelock == nil {
+				m.Timelock = &TimeLock{}
+			}
+			if err := m.Timelock.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Derive", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthCryptex
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Derive == nil {
+				m.Derive = &Derive{}
+			}
+			if err := m.Derive.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			var sizeOfWire int
 			for {
@@ -0,0 +1,103 @@
+package cryptex
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const deriveKeySize = 32
+
+// NewDerive constructs a new Derive cryptex. The HKDF cryptographic key
+// derivation function is used to derive a key for each label from a single
+// input.
+func NewDerive(labels []string, comment string) (*Derive, error) {
+	salt := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	return &Derive{
+		Salt:    salt,
+		Labels:  labels,
+		comment: comment,
+	}, nil
+}
+
+// Comment string
+func (c *Derive) Comment() string {
+	return c.comment
+}
+
+// Close derives a secret for each label from the input. Unlike other cryptexes
+// the secrets are outputs: they are overwritten with the derived keys. The
+// input key is generated if not present in the inputs data.
+func (c *Derive) Close(inputs, secrets [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) != 1 {
+		return errors.New("Derive requires 1 input")
+	}
+	if len(secrets) != len(c.Labels) {
+		return errors.New("Derive requires a secret for each label")
+	}
+
+	if len(inputs[0]) == 0 {
+		inputs[0] = make([]byte, deriveKeySize)
+		if _, err := io.ReadFull(rand.Reader, inputs[0]); err != nil {
+			return err
+		}
+	}
+
+	return c.derive(secrets, inputs[0])
+}
+
+// Open derives a secret for each label from the input.
+func (c *Derive) Open(secrets, inputs [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) != 1 {
+		return errors.New("Derive requires 1 input")
+	}
+	if len(inputs[0]) == 0 {
+		return errors.New("Derive requires a non-empty input")
+	}
+	if len(secrets) != len(c.Labels) {
+		return errors.New("Derive requires a secret for each label")
+	}
+
+	return c.derive(secrets, inputs[0])
+}
+
+func (c *Derive) derive(secrets [][]byte, key []byte) error {
+	for i, label := range c.Labels {
+		secret := make([]byte, deriveKeySize)
+		if _, err := io.ReadFull(hkdf.New(sha256.New, key, c.Salt, []byte(label)), secret); err != nil {
+			return err
+		}
+		secrets[i] = secret
+	}
+	return nil
+}
+
+func (c *Derive) validate() error {
+	if len(c.Salt) != sha256.Size {
+		return errors.New("Salt must be 32 bytes")
+	}
+	if len(c.Labels) == 0 {
+		return errors.New("Derive requires at least 1 label")
+	}
+
+	seen := make(map[string]bool, len(c.Labels))
+	for _, label := range c.Labels {
+		if seen[label] {
+			return errors.New("Derive labels must be unique")
+		}
+		seen[label] = true
+	}
+	return nil
+}
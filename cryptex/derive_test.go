@@ -0,0 +1,90 @@
+package cryptex
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDerive(t *testing.T) {
+	cptx, err := NewDerive([]string{"payroll", "backups", "escrow"}, "Derive cryptex")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := make([][]byte, 1)
+	want := make([][]byte, len(cptx.Labels))
+	if err := cptx.Close(inputs, want); err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs[0]) != 32 {
+		t.Errorf("want generated 32 byte input, got %d bytes", len(inputs[0]))
+	}
+
+	for i := range want {
+		for j := i + 1; j < len(want); j++ {
+			if bytes.Equal(want[i], want[j]) {
+				t.Errorf("want distinct keys for %q & %q", cptx.Labels[i], cptx.Labels[j])
+			}
+		}
+	}
+
+	got := make([][]byte, len(want))
+	if err := cptx.Open(got, inputs); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want secrets %x, got %x", want, got)
+	}
+
+	// a given input is kept
+	inputs = [][]byte{[]byte("super secret password")}
+	if err := cptx.Close(inputs, want); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "super secret password", string(inputs[0]); want != got {
+		t.Errorf("want input %q, got %q", want, got)
+	}
+	if err := cptx.Open(got, inputs); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want secrets %x, got %x", want, got)
+	}
+
+	if err := cptx.Open(got, [][]byte{nil}); err == nil {
+		t.Errorf("derive cryptex opened without non-nil input")
+	}
+	if err := cptx.Open(got[:2], inputs); err == nil {
+		t.Errorf("derive cryptex opened with too few secrets")
+	}
+
+	dup, err := NewDerive([]string{"payroll", "payroll"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dup.Close(make([][]byte, 1), make([][]byte, 2)); err == nil {
+		t.Errorf("derive cryptex closed with duplicate labels")
+	}
+}
+
+func TestRoundTripDerive(t *testing.T) {
+	want, err := NewDerive([]string{"payroll", "backups"}, "Derive cryptex")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got.(*Derive)) {
+		t.Errorf("want Derive cryptex %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,40 @@
+package cryptex
+
+// GF(2^16) arithmetic with the primitive polynomial x^16+x^5+x^3+x^2+1.
+const gf16Poly = 0x1002d
+
+var (
+	gf16Exp [2 * 65535]uint16
+	gf16Log [65536]uint16
+)
+
+func init() {
+	x := uint32(1)
+	for i := 0; i < 65535; i++ {
+		gf16Exp[i] = uint16(x)
+		gf16Exp[i+65535] = uint16(x)
+		gf16Log[x] = uint16(i)
+
+		x <<= 1
+		if x&0x10000 != 0 {
+			x ^= gf16Poly
+		}
+	}
+}
+
+func gf16Mul(a, b uint16) uint16 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gf16Exp[int(gf16Log[a])+int(gf16Log[b])]
+}
+
+func gf16Div(a, b uint16) uint16 {
+	if b == 0 {
+		panic("gf16: division by zero")
+	}
+	if a == 0 {
+		return 0
+	}
+	return gf16Exp[int(gf16Log[a])+65535-int(gf16Log[b])]
+}
@@ -0,0 +1,240 @@
+package cryptex
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/mlkem"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+const (
+	// HybridKEMPublicKeySize is the size of an ML-KEM-768 encapsulation key
+	// followed by an X25519 public key.
+	HybridKEMPublicKeySize = mlkem.EncapsulationKeySize768 + 32
+
+	// HybridKEMPrivateKeySize is the size of an ML-KEM-768 decapsulation key
+	// seed followed by an X25519 private key.
+	HybridKEMPrivateKeySize = mlkem.SeedSize + 32
+
+	hybridKEMInfo = "vcrypt hybrid-kem mlkem768-x25519"
+)
+
+// NewHybridKEM constructs a new HybridKEM for the combined ML-KEM-768 &
+// X25519 PublicKey.
+func NewHybridKEM(publicKey []byte, comment string) *HybridKEM {
+	return &HybridKEM{
+		PublicKey: publicKey,
+		comment:   comment,
+	}
+}
+
+// GenerateHybridKEMKey returns a new private key & the matching public key.
+func GenerateHybridKEMKey(rand io.Reader) (privateKey, publicKey []byte, err error) {
+	privateKey = make([]byte, HybridKEMPrivateKeySize)
+	if _, err := io.ReadFull(rand, privateKey); err != nil {
+		return nil, nil, err
+	}
+
+	mk, xk, err := parseHybridKEMPrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return privateKey, hybridKEMPublicKey(mk, xk), nil
+}
+
+// HybridKEMPublicKey returns the public key for a private key.
+func HybridKEMPublicKey(privateKey []byte) ([]byte, error) {
+	mk, xk, err := parseHybridKEMPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return hybridKEMPublicKey(mk, xk), nil
+}
+
+// Comment string
+func (c *HybridKEM) Comment() string {
+	return c.comment
+}
+
+// Close seals the secret to the PublicKey. A shared secret is encapsulated to
+// the ML-KEM-768 key and another is agreed with an ephemeral X25519 key. Both
+// are combined with HKDF into the secretbox key, so the secret stays sealed
+// unless both are broken. The ML-KEM ciphertext, ephemeral public key, and
+// secretbox are stored in the input data.
+func (c *HybridKEM) Close(inputs, secrets [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) != 2 {
+		return errors.New("HybridKEM requires 2 inputs")
+	}
+	if len(secrets) != 1 {
+		return errors.New("HybridKEM supports 1 secret")
+	}
+	secret := secrets[0]
+
+	ek, peerKey, err := c.publicKeys()
+	if err != nil {
+		return err
+	}
+
+	mss, mct := ek.Encapsulate()
+
+	ephKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	xss, err := ephKey.ECDH(peerKey)
+	if err != nil {
+		return err
+	}
+
+	ephPub := ephKey.PublicKey().Bytes()
+	key, err := c.sharedKey(mss, xss, mct, ephPub)
+	if err != nil {
+		return err
+	}
+
+	nonce := [24]byte{}
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return err
+	}
+
+	out := make([]byte, 0, len(mct)+len(ephPub)+24+len(secret)+secretbox.Overhead)
+	out = append(out, mct...)
+	out = append(out, ephPub...)
+	out = append(out, nonce[:]...)
+
+	inputs[0] = secretbox.Seal(out, secret, &nonce, key)
+	inputs[1] = nil
+	return nil
+}
+
+// Open unseals a secret from the ciphertext & private key portions of the
+// input data.
+func (c *HybridKEM) Open(secrets, inputs [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) != 2 {
+		return errors.New("len(inputs) must be 2")
+	}
+
+	mk, xk, err := parseHybridKEMPrivateKey(inputs[1])
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(hybridKEMPublicKey(mk, xk), c.PublicKey) {
+		return errors.New("wrong private key for public key")
+	}
+
+	data := inputs[0]
+	if len(data) < mlkem.CiphertextSize768+32+24+secretbox.Overhead {
+		return errors.New("invalid box")
+	}
+	mct, data := data[:mlkem.CiphertextSize768], data[mlkem.CiphertextSize768:]
+	ephPub, data := data[:32], data[32:]
+
+	mss, err := mk.Decapsulate(mct)
+	if err != nil {
+		return err
+	}
+
+	ephKey, err := ecdh.X25519().NewPublicKey(ephPub)
+	if err != nil {
+		return err
+	}
+
+	xss, err := xk.ECDH(ephKey)
+	if err != nil {
+		return err
+	}
+
+	key, err := c.sharedKey(mss, xss, mct, ephPub)
+	if err != nil {
+		return err
+	}
+
+	nonce := [24]byte{}
+	copy(nonce[:], data[:24])
+
+	secret, ok := secretbox.Open(nil, data[24:], &nonce, key)
+	if !ok {
+		return errors.New("decryption failure")
+	}
+
+	secrets[0] = secret
+	return nil
+}
+
+// sharedKey derives the secretbox key from both shared secrets.
+func (c *HybridKEM) sharedKey(mss, xss, mct, ephPub []byte) (*[32]byte, error) {
+	// HKDF(mlkem shared|x25519 shared, salt=mlkem ciphertext|ephemeral, info=PublicKey)
+	ikm := append(append([]byte{}, mss...), xss...)
+	salt := append(append([]byte{}, mct...), ephPub...)
+	info := append([]byte(hybridKEMInfo), c.PublicKey...)
+
+	kdf := hkdf.New(sha256.New, ikm, salt, info)
+
+	key := [32]byte{}
+	if _, err := io.ReadFull(kdf, key[:]); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (c *HybridKEM) publicKeys() (*mlkem.EncapsulationKey768, *ecdh.PublicKey, error) {
+	ek, err := mlkem.NewEncapsulationKey768(c.PublicKey[:mlkem.EncapsulationKeySize768])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	xk, err := ecdh.X25519().NewPublicKey(c.PublicKey[mlkem.EncapsulationKeySize768:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ek, xk, nil
+}
+
+func (c *HybridKEM) validate() error {
+	if len(c.PublicKey) == 0 {
+		return errors.New("PublicKey missing")
+	}
+	if len(c.PublicKey) != HybridKEMPublicKeySize {
+		return errors.New("invalid hybrid KEM public key size")
+	}
+	_, _, err := c.publicKeys()
+	return err
+}
+
+func parseHybridKEMPrivateKey(data []byte) (*mlkem.DecapsulationKey768, *ecdh.PrivateKey, error) {
+	if len(data) != HybridKEMPrivateKeySize {
+		return nil, nil, errors.New("invalid hybrid KEM private key")
+	}
+
+	mk, err := mlkem.NewDecapsulationKey768(data[:mlkem.SeedSize])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	xk, err := ecdh.X25519().NewPrivateKey(data[mlkem.SeedSize:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mk, xk, nil
+}
+
+func hybridKEMPublicKey(mk *mlkem.DecapsulationKey768, xk *ecdh.PrivateKey) []byte {
+	pub := append([]byte{}, mk.EncapsulationKey().Bytes()...)
+	return append(pub, xk.PublicKey().Bytes()...)
+}
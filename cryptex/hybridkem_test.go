@@ -0,0 +1,95 @@
+package cryptex
+
+import (
+	"bytes"
+	"crypto/mlkem"
+	"crypto/rand"
+	"reflect"
+	"testing"
+)
+
+func TestHybridKEM(t *testing.T) {
+	want := [][]byte{[]byte("super secret password")}
+	sk, pk, err := GenerateHybridKEMKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cptx := NewHybridKEM(pk, "HybridKEM cryptex")
+
+	inputs := make([][]byte, 2)
+	if err := cptx.Close(inputs, want); err != nil {
+		t.Fatal(err)
+	}
+	if inputs[1] != nil {
+		t.Errorf("want inputs[1] to be nil, got %v", inputs[1])
+	}
+	inputs[1] = sk
+
+	got := make([][]byte, len(want))
+	if err := cptx.Open(got, inputs); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want secret %q, got %q", want, got)
+	}
+
+	// tamper with the ML-KEM ciphertext, then the X25519 ephemeral key
+	for _, i := range []int{0, mlkem.CiphertextSize768} {
+		data := append([]byte{}, inputs[0]...)
+		data[i] ^= 1
+		if err := cptx.Open(got, [][]byte{data, sk}); err == nil {
+			t.Errorf("HybridKEM cryptex opened with tampered byte %d", i)
+		}
+	}
+
+	other, _, err := GenerateHybridKEMKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cptx.Open(got, [][]byte{inputs[0], other}); err == nil {
+		t.Errorf("HybridKEM cryptex opened with wrong private key")
+	}
+}
+
+func TestHybridKEMPublicKey(t *testing.T) {
+	sk, pk, err := GenerateHybridKEMKey(bytes.NewReader(bytes.Repeat([]byte{0x42}, HybridKEMPrivateKeySize)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := HybridKEMPublicKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pk, got) {
+		t.Errorf("want public key %x, got %x", pk, got)
+	}
+
+	if _, err := HybridKEMPublicKey(sk[1:]); err == nil {
+		t.Errorf("want error for short private key, got nil")
+	}
+}
+
+func TestRoundTripHybridKEM(t *testing.T) {
+	_, pk, err := GenerateHybridKEMKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := NewHybridKEM(pk, "HybridKEM cryptex")
+
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got.(*HybridKEM)) {
+		t.Errorf("want HybridKEM cryptex %v, got %v", want, got)
+	}
+}
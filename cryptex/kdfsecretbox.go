@@ -0,0 +1,228 @@
+package cryptex
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const kdfSaltSize = 16
+
+// Limits on the KDF cost parameters. The parameters are read from the vault,
+// so they are capped to keep a crafted vault from exhausting memory or CPU.
+// Both KDFs share the memory budget: argon2id uses Memory KiB, scrypt uses
+// 128*r*2^log-n bytes.
+const (
+	maxKDFMemory = 4 << 30 // bytes, 4 GiB
+
+	maxArgon2idTime = 16
+	maxScryptLogN   = 22
+	maxScryptR      = 32
+	maxScryptP      = 8
+)
+
+// NewArgon2idSecretBox constructs a new KDFSecretBox that derives the key
+// from the input with Argon2id. Memory is in KiB.
+func NewArgon2idSecretBox(time, memory, threads uint32, comment string) (*KDFSecretBox, error) {
+	c := &KDFSecretBox{
+		Argon2id: &Argon2id{
+			Time:    time,
+			Memory:  memory,
+			Threads: threads,
+		},
+		comment: comment,
+	}
+
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewScryptSecretBox constructs a new KDFSecretBox that derives the key from
+// the input with scrypt, where N is 2^logN.
+func NewScryptSecretBox(logN, r, p uint32, comment string) (*KDFSecretBox, error) {
+	c := &KDFSecretBox{
+		Scrypt: &Scrypt{
+			LogN: logN,
+			R:    r,
+			P:    p,
+		},
+		comment: comment,
+	}
+
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Comment string
+func (c *KDFSecretBox) Comment() string {
+	return c.comment
+}
+
+// Cost describes the KDF & cost parameters.
+func (c *KDFSecretBox) Cost() string {
+	switch {
+	case c.Argon2id != nil:
+		return fmt.Sprintf("argon2id t=%d m=%dKiB p=%d", c.Argon2id.Time, c.Argon2id.Memory, c.Argon2id.Threads)
+	case c.Scrypt != nil:
+		return fmt.Sprintf("scrypt N=2^%d r=%d p=%d", c.Scrypt.LogN, c.Scrypt.R, c.Scrypt.P)
+	default:
+		return "none"
+	}
+}
+
+// Close seals the secret to a key derived from the input key. The input key
+// is generated if not present in the inputs data.
+func (c *KDFSecretBox) Close(inputs, secrets [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) != 2 {
+		return errors.New("KDFSecretBox supports exactly 2 inputs")
+	}
+	if len(secrets) != 1 {
+		return errors.New("KDFSecretBox supports only a single secret")
+	}
+
+	secret := secrets[0]
+	nonce := [24]byte{}
+
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return err
+	}
+
+	pass := inputs[0]
+	if len(pass) == 0 {
+		pass = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, pass); err != nil {
+			return err
+		}
+	}
+
+	key, err := c.deriveKey(pass)
+	if err != nil {
+		return err
+	}
+
+	out := make([]byte, 24+len(secret)+secretbox.Overhead)
+	copy(out[:24], nonce[:])
+
+	secretbox.Seal(out[24:24], secret, &nonce, key)
+	inputs[0] = pass
+	inputs[1] = out
+	return nil
+}
+
+// Open unseals a secret with the key derived from the input key.
+func (c *KDFSecretBox) Open(secrets, inputs [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) != 2 {
+		return errors.New("len(inputs) must be 2")
+	}
+	if len(secrets) != 1 {
+		return errors.New("Too many secrets expected")
+	}
+
+	nbox := inputs[1]
+	if len(nbox) < 24+secretbox.Overhead {
+		return errors.New("invalid box")
+	}
+
+	key, err := c.deriveKey(inputs[0])
+	if err != nil {
+		return err
+	}
+
+	nonce := [24]byte{}
+	copy(nonce[:], nbox[:24])
+
+	secret, ok := secretbox.Open(nil, nbox[24:], &nonce, key)
+	if !ok {
+		return errors.New("decryption failure")
+	}
+
+	secrets[0] = secret
+	return nil
+}
+
+func (c *KDFSecretBox) deriveKey(pass []byte) (*[32]byte, error) {
+	var dk []byte
+
+	switch {
+	case c.Argon2id != nil:
+		p := c.Argon2id
+		dk = argon2.IDKey(pass, c.Salt, p.Time, p.Memory, uint8(p.Threads), 32)
+	case c.Scrypt != nil:
+		p := c.Scrypt
+
+		var err error
+		if dk, err = scrypt.Key(pass, c.Salt, 1<<p.LogN, int(p.R), int(p.P), 32); err != nil {
+			return nil, err
+		}
+	}
+
+	key := [32]byte{}
+	copy(key[:], dk)
+	return &key, nil
+}
+
+func (c *KDFSecretBox) init() error {
+	c.Salt = make([]byte, kdfSaltSize)
+	if _, err := io.ReadFull(rand.Reader, c.Salt); err != nil {
+		return err
+	}
+
+	return c.validate()
+}
+
+func (c *KDFSecretBox) validate() error {
+	if len(c.Salt) < kdfSaltSize {
+		return errors.New("KDFSecretBox salt must be at least 16 bytes")
+	}
+
+	switch {
+	case c.Argon2id != nil && c.Scrypt != nil:
+		return errors.New("KDFSecretBox supports only one of argon2id or scrypt")
+	case c.Argon2id != nil:
+		p := c.Argon2id
+		if p.Time < 1 || p.Time > maxArgon2idTime {
+			return fmt.Errorf("argon2id time must be between 1 and %d", maxArgon2idTime)
+		}
+		if p.Threads < 1 || p.Threads > 255 {
+			return errors.New("argon2id threads must be between 1 and 255")
+		}
+		if p.Memory < 8*p.Threads {
+			return errors.New("argon2id memory must be at least 8KiB per thread")
+		}
+		if uint64(p.Memory)*1024 > maxKDFMemory {
+			return errors.New("argon2id memory must be at most 4GiB")
+		}
+	case c.Scrypt != nil:
+		p := c.Scrypt
+		if p.LogN < 1 || p.LogN > maxScryptLogN {
+			return fmt.Errorf("scrypt log-n must be between 1 and %d", maxScryptLogN)
+		}
+		if p.R < 1 || p.R > maxScryptR {
+			return fmt.Errorf("scrypt r must be between 1 and %d", maxScryptR)
+		}
+		if p.P < 1 || p.P > maxScryptP {
+			return fmt.Errorf("scrypt p must be between 1 and %d", maxScryptP)
+		}
+		if 128*uint64(p.R)<<p.LogN > maxKDFMemory {
+			return errors.New("scrypt memory 128*r*2^log-n must be at most 4GiB")
+		}
+	default:
+		return errors.New("KDFSecretBox requires argon2id or scrypt parameters")
+	}
+	return nil
+}
@@ -0,0 +1,705 @@
+// Code generated by protoc-gen-gogo.
+// source: cryptex/kdfsecretbox.proto
+// DO NOT EDIT!
+
+package cryptex
+
+import proto "github.com/gogo/protobuf/proto"
+
+// discarding unused import gogoproto "github.com/gogo/protobuf/gogoproto"
+
+import io "io"
+import fmt "fmt"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+
+type Argon2id struct {
+	Time    uint32 `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Memory  uint32 `protobuf:"varint,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	Threads uint32 `protobuf:"varint,3,opt,name=threads,proto3" json:"threads,omitempty"`
+}
+
+func (m *Argon2id) Reset()         { *m = Argon2id{} }
+func (m *Argon2id) String() string { return proto.CompactTextString(m) }
+func (*Argon2id) ProtoMessage()    {}
+
+type Scrypt struct {
+	LogN uint32 `protobuf:"varint,1,opt,name=log_n,proto3" json:"log_n,omitempty"`
+	R    uint32 `protobuf:"varint,2,opt,name=r,proto3" json:"r,omitempty"`
+	P    uint32 `protobuf:"varint,3,opt,name=p,proto3" json:"p,omitempty"`
+}
+
+func (m *Scrypt) Reset()         { *m = Scrypt{} }
+func (m *Scrypt) String() string { return proto.CompactTextString(m) }
+func (*Scrypt) ProtoMessage()    {}
+
+type KDFSecretBox struct {
+	comment  string    `protobuf:"bytes,1,opt,name=comment,proto3" json:"comment,omitempty"`
+	Salt     []byte    `protobuf:"bytes,2,opt,name=salt,proto3" json:"salt,omitempty"`
+	Argon2id *Argon2id `protobuf:"bytes,3,opt,name=argon2id" json:"argon2id,omitempty"`
+	Scrypt   *Scrypt   `protobuf:"bytes,4,opt,name=scrypt" json:"scrypt,omitempty"`
+}
+
+func (m *KDFSecretBox) Reset()         { *m = KDFSecretBox{} }
+func (m *KDFSecretBox) String() string { return proto.CompactTextString(m) }
+func (*KDFSecretBox) ProtoMessage()    {}
+
+func (m *KDFSecretBox) GetArgon2id() *Argon2id {
+	if m != nil {
+		return m.Argon2id
+	}
+	return nil
+}
+
+func (m *KDFSecretBox) GetScrypt() *Scrypt {
+	if m != nil {
+		return m.Scrypt
+	}
+	return nil
+}
+
+func (m *Argon2id) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *Argon2id) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Time != 0 {
+		data[i] = 0x8
+		i++
+		i = encodeVarintKdfsecretbox(data, i, uint64(m.Time))
+	}
+	if m.Memory != 0 {
+		data[i] = 0x10
+		i++
+		i = encodeVarintKdfsecretbox(data, i, uint64(m.Memory))
+	}
+	if m.Threads != 0 {
+		data[i] = 0x18
+		i++
+		i = encodeVarintKdfsecretbox(data, i, uint64(m.Threads))
+	}
+	return i, nil
+}
+
+func (m *Scrypt) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *Scrypt) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.LogN != 0 {
+		data[i] = 0x8
+		i++
+		i = encodeVarintKdfsecretbox(data, i, uint64(m.LogN))
+	}
+	if m.R != 0 {
+		data[i] = 0x10
+		i++
+		i = encodeVarintKdfsecretbox(data, i, uint64(m.R))
+	}
+	if m.P != 0 {
+		data[i] = 0x18
+		i++
+		i = encodeVarintKdfsecretbox(data, i, uint64(m.P))
+	}
+	return i, nil
+}
+
+func (m *KDFSecretBox) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *KDFSecretBox) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.comment) > 0 {
+		data[i] = 0xa
+		i++
+		i = encodeVarintKdfsecretbox(data, i, uint64(len(m.comment)))
+		i += copy(data[i:], m.comment)
+	}
+	if m.Salt != nil {
+		if len(m.Salt) > 0 {
+			data[i] = 0x12
+			i++
+			i = encodeVarintKdfsecretbox(data, i, uint64(len(m.Salt)))
+			i += copy(data[i:], m.Salt)
+		}
+	}
+	if m.Argon2id != nil {
+		data[i] = 0x1a
+		i++
+		i = encodeVarintKdfsecretbox(data, i, uint64(m.Argon2id.Size()))
+		n1, err := m.Argon2id.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.Scrypt != nil {
+		data[i] = 0x22
+		i++
+		i = encodeVarintKdfsecretbox(data, i, uint64(m.Scrypt.Size()))
+		n2, err := m.Scrypt.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	return i, nil
+}
+
+func encodeFixed64Kdfsecretbox(data []byte, offset int, v uint64) int {
+	data[offset] = uint8(v)
+	data[offset+1] = uint8(v >> 8)
+	data[offset+2] = uint8(v >> 16)
+	data[offset+3] = uint8(v >> 24)
+	data[offset+4] = uint8(v >> 32)
+	data[offset+5] = uint8(v >> 40)
+	data[offset+6] = uint8(v >> 48)
+	data[offset+7] = uint8(v >> 56)
+	return offset + 8
+}
+func encodeFixed32Kdfsecretbox(data []byte, offset int, v uint32) int {
+	data[offset] = uint8(v)
+	data[offset+1] = uint8(v >> 8)
+	data[offset+2] = uint8(v >> 16)
+	data[offset+3] = uint8(v >> 24)
+	return offset + 4
+}
+func encodeVarintKdfsecretbox(data []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		data[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	data[offset] = uint8(v)
+	return offset + 1
+}
+func (m *Argon2id) Size() (n int) {
+	var l int
+	_ = l
+	if m.Time != 0 {
+		n += 1 + sovKdfsecretbox(uint64(m.Time))
+	}
+	if m.Memory != 0 {
+		n += 1 + sovKdfsecretbox(uint64(m.Memory))
+	}
+	if m.Threads != 0 {
+		n += 1 + sovKdfsecretbox(uint64(m.Threads))
+	}
+	return n
+}
+
+func (m *Scrypt) Size() (n int) {
+	var l int
+	_ = l
+	if m.LogN != 0 {
+		n += 1 + sovKdfsecretbox(uint64(m.LogN))
+	}
+	if m.R != 0 {
+		n += 1 + sovKdfsecretbox(uint64(m.R))
+	}
+	if m.P != 0 {
+		n += 1 + sovKdfsecretbox(uint64(m.P))
+	}
+	return n
+}
+
+func (m *KDFSecretBox) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.comment)
+	if l > 0 {
+		n += 1 + l + sovKdfsecretbox(uint64(l))
+	}
+	if m.Salt != nil {
+		l = len(m.Salt)
+		if l > 0 {
+			n += 1 + l + sovKdfsecretbox(uint64(l))
+		}
+	}
+	if m.Argon2id != nil {
+		l = m.Argon2id.Size()
+		n += 1 + l + sovKdfsecretbox(uint64(l))
+	}
+	if m.Scrypt != nil {
+		l = m.Scrypt.Size()
+		n += 1 + l + sovKdfsecretbox(uint64(l))
+	}
+	return n
+}
+
+func sovKdfsecretbox(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozKdfsecretbox(x uint64) (n int) {
+	return sovKdfsecretbox(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Argon2id) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Time", wireType)
+			}
+			m.Time = 0
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Time |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memory", wireType)
+			}
+			m.Memory = 0
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Memory |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Threads", wireType)
+			}
+			m.Threads = 0
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Threads |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			var sizeOfWire int
+			for {
+				sizeOfWire++
+				wire >>= 7
+				if wire == 0 {
+					break
+				}
+			}
+			iNdEx -= sizeOfWire
+			skippy, err := skipKdfsecretbox(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthKdfsecretbox
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	return nil
+}
+func (m *Scrypt) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LogN", wireType)
+			}
+			m.LogN = 0
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.LogN |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field R", wireType)
+			}
+			m.R = 0
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.R |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field P", wireType)
+			}
+			m.P = 0
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.P |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			var sizeOfWire int
+			for {
+				sizeOfWire++
+				wire >>= 7
+				if wire == 0 {
+					break
+				}
+			}
+			iNdEx -= sizeOfWire
+			skippy, err := skipKdfsecretbox(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthKdfsecretbox
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	return nil
+}
+func (m *KDFSecretBox) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field comment", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + int(stringLen)
+			if stringLen < 0 {
+				return ErrInvalidLengthKdfsecretbox
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.comment = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Salt", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthKdfsecretbox
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Salt = append([]byte{}, data[iNdEx:postIndex]...)
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Argon2id", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthKdfsecretbox
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Argon2id == nil {
+				m.Argon2id = &Argon2id{}
+			}
+			if err := m.Argon2id.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scrypt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthKdfsecretbox
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Scrypt == nil {
+				m.Scrypt = &Scrypt{}
+			}
+			if err := m.Scrypt.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			var sizeOfWire int
+			for {
+				sizeOfWire++
+				wire >>= 7
+				if wire == 0 {
+					break
+				}
+			}
+			iNdEx -= sizeOfWire
+			skippy, err := skipKdfsecretbox(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthKdfsecretbox
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	return nil
+}
+func skipKdfsecretbox(data []byte) (n int, err error) {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for {
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if data[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthKdfsecretbox
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := data[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipKdfsecretbox(data[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthKdfsecretbox = fmt.Errorf("proto: negative length found during unmarshaling")
+)
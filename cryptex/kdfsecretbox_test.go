@@ -0,0 +1,95 @@
+package cryptex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKDFSecretBox(t *testing.T) {
+	argon, err := NewArgon2idSecretBox(1, 64, 1, "argon2id KDFSecretBox cryptex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scrypt, err := NewScryptSecretBox(4, 8, 1, "scrypt KDFSecretBox cryptex")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cptx := range []*KDFSecretBox{argon, scrypt} {
+		want := [][]byte{[]byte("super secret password")}
+
+		pass := []byte("kdf secretbox pass")
+		inputs := [][]byte{pass, nil}
+		if err := cptx.Close(inputs, want); err != nil {
+			t.Fatal(err)
+		}
+
+		got := make([][]byte, len(want))
+		if err := cptx.Open(got, [][]byte{pass, inputs[1]}); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("want secret %q, got %q", want, got)
+		}
+
+		if err := cptx.Open(got, [][]byte{[]byte("wrong pass"), inputs[1]}); err == nil {
+			t.Errorf("%s cryptex unsealed with wrong password", cptx.Cost())
+		}
+
+		salt := cptx.Salt
+		cptx.Salt = make([]byte, len(salt))
+		if err := cptx.Open(got, [][]byte{pass, inputs[1]}); err == nil {
+			t.Errorf("%s cryptex unsealed with wrong salt", cptx.Cost())
+		}
+		cptx.Salt = salt
+	}
+
+	limits := []struct {
+		name string
+		new  func() (*KDFSecretBox, error)
+		ok   bool
+	}{
+		{"argon2id memory below 8KiB", func() (*KDFSecretBox, error) { return NewArgon2idSecretBox(1, 4, 1, "") }, false},
+		{"argon2id memory of 4GiB", func() (*KDFSecretBox, error) { return NewArgon2idSecretBox(1, 4<<20, 1, "") }, true},
+		{"argon2id memory above 4GiB", func() (*KDFSecretBox, error) { return NewArgon2idSecretBox(1, 4<<20+1, 1, "") }, false},
+		{"argon2id time of 16", func() (*KDFSecretBox, error) { return NewArgon2idSecretBox(16, 64, 1, "") }, true},
+		{"argon2id time of 17", func() (*KDFSecretBox, error) { return NewArgon2idSecretBox(17, 64, 1, "") }, false},
+		{"scrypt log-n of 0", func() (*KDFSecretBox, error) { return NewScryptSecretBox(0, 8, 1, "") }, false},
+		{"scrypt log-n of 23", func() (*KDFSecretBox, error) { return NewScryptSecretBox(23, 1, 1, "") }, false},
+		{"scrypt r of 33", func() (*KDFSecretBox, error) { return NewScryptSecretBox(17, 33, 1, "") }, false},
+		{"scrypt p of 8", func() (*KDFSecretBox, error) { return NewScryptSecretBox(17, 8, 8, "") }, true},
+		{"scrypt p of 9", func() (*KDFSecretBox, error) { return NewScryptSecretBox(17, 8, 9, "") }, false},
+		{"scrypt memory of 4GiB", func() (*KDFSecretBox, error) { return NewScryptSecretBox(22, 8, 1, "") }, true},
+		{"scrypt memory above 4GiB", func() (*KDFSecretBox, error) { return NewScryptSecretBox(22, 9, 1, "") }, false},
+		{"scrypt memory of 16GiB", func() (*KDFSecretBox, error) { return NewScryptSecretBox(22, 32, 1, "") }, false},
+	}
+	for _, limit := range limits {
+		if _, err := limit.new(); (err == nil) != limit.ok {
+			t.Errorf("%s: want ok %t, got %v", limit.name, limit.ok, err)
+		}
+	}
+}
+
+func TestRoundTripKDFSecretBox(t *testing.T) {
+	want, err := NewArgon2idSecretBox(3, 64*1024, 4, "KDFSecretBox cryptex")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got.(*KDFSecretBox)) {
+		t.Errorf("want KDFSecretBox cryptex %v, got %v", want, got)
+	}
+	if want, got := "argon2id t=3 m=65536KiB p=4", got.(*KDFSecretBox).Cost(); want != got {
+		t.Errorf("want cost %q, got %q", want, got)
+	}
+}
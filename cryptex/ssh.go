@@ -0,0 +1,216 @@
+package cryptex
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"errors"
+	"io"
+
+	"filippo.io/edwards25519"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/ssh"
+)
+
+// NewSSH constructs a new SSH for an ssh-ed25519 or ecdsa-sha2-nistp* public
+// key in the SSH wire format.
+func NewSSH(publicKey []byte, comment string) *SSH {
+	return &SSH{
+		PublicKey: publicKey,
+		comment:   comment,
+	}
+}
+
+// Comment string
+func (c *SSH) Comment() string {
+	return c.comment
+}
+
+// Close seals the secret to the PublicKey. An ephemeral ECDH key agreement is
+// made with the X25519 form of an ssh-ed25519 key, like age, or with the
+// curve of an ecdsa key, and the shared secret is expanded with HKDF into a
+// secretbox key. The ephemeral public key & ciphertext are stored in the
+// input data.
+func (c *SSH) Close(inputs, secrets [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) != 2 {
+		return errors.New("SSH requires 2 inputs")
+	}
+	if len(secrets) != 1 {
+		return errors.New("SSH supports 1 secret")
+	}
+	secret := secrets[0]
+
+	peerKey, err := c.ecdhPublicKey()
+	if err != nil {
+		return err
+	}
+
+	ephKey, err := peerKey.Curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	shared, err := ephKey.ECDH(peerKey)
+	if err != nil {
+		return err
+	}
+
+	ephPub := ephKey.PublicKey().Bytes()
+	key, err := c.sharedKey(shared, ephPub, peerKey.Bytes())
+	if err != nil {
+		return err
+	}
+
+	nonce := [24]byte{}
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return err
+	}
+
+	out := make([]byte, 0, len(ephPub)+24+len(secret)+secretbox.Overhead)
+	out = append(out, ephPub...)
+	out = append(out, nonce[:]...)
+
+	inputs[0] = secretbox.Seal(out, secret, &nonce, key)
+	inputs[1] = nil
+	return nil
+}
+
+// Open unseals a secret from the ciphertext & private key portions of the
+// input data. The private key is PKCS #8 DER encoded.
+func (c *SSH) Open(secrets, inputs [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) != 2 {
+		return errors.New("len(inputs) must be 2")
+	}
+
+	privKey, err := c.ecdhPrivateKey(inputs[1])
+	if err != nil {
+		return err
+	}
+
+	data, klen := inputs[0], len(privKey.PublicKey().Bytes())
+	if len(data) < klen+24+secretbox.Overhead {
+		return errors.New("invalid box")
+	}
+
+	ephKey, err := privKey.Curve().NewPublicKey(data[:klen])
+	if err != nil {
+		return err
+	}
+
+	shared, err := privKey.ECDH(ephKey)
+	if err != nil {
+		return err
+	}
+
+	key, err := c.sharedKey(shared, ephKey.Bytes(), privKey.PublicKey().Bytes())
+	if err != nil {
+		return err
+	}
+
+	nonce := [24]byte{}
+	copy(nonce[:], data[klen:klen+24])
+
+	secret, ok := secretbox.Open(nil, data[klen+24:], &nonce, key)
+	if !ok {
+		return errors.New("decryption failure")
+	}
+
+	secrets[0] = secret
+	return nil
+}
+
+// sharedKey derives the secretbox key from the ECDH shared secret.
+func (c *SSH) sharedKey(shared, ephPub, peerPub []byte) (*[32]byte, error) {
+	// HKDF(shared, salt=ephemeral|recipient, info=PublicKey)
+	salt := append(append([]byte{}, ephPub...), peerPub...)
+	kdf := hkdf.New(sha256.New, shared, salt, c.PublicKey)
+
+	key := [32]byte{}
+	if _, err := io.ReadFull(kdf, key[:]); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (c *SSH) ecdhPublicKey() (*ecdh.PublicKey, error) {
+	pub, err := ssh.ParsePublicKey(c.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	cpub, ok := pub.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, errors.New("unsupported ssh key type")
+	}
+
+	switch pub := cpub.CryptoPublicKey().(type) {
+	case ed25519.PublicKey:
+		pt, err := new(edwards25519.Point).SetBytes(pub)
+		if err != nil {
+			return nil, err
+		}
+		return ecdh.X25519().NewPublicKey(pt.BytesMontgomery())
+	case *ecdsa.PublicKey:
+		return pub.ECDH()
+	default:
+		return nil, errors.New("unsupported ssh key type, must be ssh-ed25519 or ecdsa")
+	}
+}
+
+func (c *SSH) ecdhPrivateKey(der []byte) (*ecdh.PrivateKey, error) {
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		pub  interface{}
+		priv *ecdh.PrivateKey
+	)
+
+	switch key := key.(type) {
+	case ed25519.PrivateKey:
+		digest := sha512.Sum512(key.Seed())
+		if priv, err = ecdh.X25519().NewPrivateKey(digest[:32]); err != nil {
+			return nil, err
+		}
+		pub = key.Public()
+	case *ecdsa.PrivateKey:
+		if priv, err = key.ECDH(); err != nil {
+			return nil, err
+		}
+		pub = key.Public()
+	default:
+		return nil, errors.New("unsupported private key type")
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(sshPub.Marshal(), c.PublicKey) {
+		return nil, errors.New("wrong private key for public key")
+	}
+
+	return priv, nil
+}
+
+func (c *SSH) validate() error {
+	if len(c.PublicKey) == 0 {
+		return errors.New("PublicKey missing")
+	}
+	_, err := c.ecdhPublicKey()
+	return err
+}
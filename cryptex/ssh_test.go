@@ -0,0 +1,110 @@
+package cryptex
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSSH(t *testing.T) {
+	for _, priv := range sshTestKeys(t) {
+		want := [][]byte{[]byte("super secret password")}
+
+		cptx := NewSSH(sshPublicKey(t, priv), "SSH cryptex")
+
+		inputs := make([][]byte, 2)
+		if err := cptx.Close(inputs, want); err != nil {
+			t.Fatal(err)
+		}
+		if inputs[1] != nil {
+			t.Errorf("want inputs[1] to be nil, got %v", inputs[1])
+		}
+
+		der, err := x509.MarshalPKCS8PrivateKey(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs[1] = der
+
+		got := make([][]byte, len(want))
+		if err := cptx.Open(got, inputs); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("want secret %q, got %q", want, got)
+		}
+
+		inputs[0][len(inputs[0])-1] ^= 1
+		if err := cptx.Open(got, inputs); err == nil {
+			t.Errorf("ssh cryptex opened with bad ciphertext")
+		}
+	}
+}
+
+func TestSSHWrongKey(t *testing.T) {
+	keys := sshTestKeys(t)
+
+	cptx := NewSSH(sshPublicKey(t, keys[0]), "SSH cryptex")
+
+	inputs := make([][]byte, 2)
+	if err := cptx.Close(inputs, [][]byte{[]byte("secret")}); err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(keys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs[1] = der
+
+	if err := cptx.Open(make([][]byte, 1), inputs); err == nil {
+		t.Errorf("ssh cryptex opened with wrong private key")
+	}
+}
+
+func TestRoundTripSSH(t *testing.T) {
+	want := NewSSH(sshPublicKey(t, sshTestKeys(t)[0]), "SSH cryptex")
+
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got.(*SSH)) {
+		t.Errorf("want SSH cryptex %v, got %v", want, got)
+	}
+}
+
+func sshTestKeys(t *testing.T) []crypto.Signer {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return []crypto.Signer{edKey, ecKey}
+}
+
+func sshPublicKey(t *testing.T, priv crypto.Signer) []byte {
+	pub, err := ssh.NewPublicKey(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pub.Marshal()
+}
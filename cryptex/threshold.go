@@ -0,0 +1,161 @@
+package cryptex
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxThresholdShares is the maximum N of a Threshold cryptex.
+const MaxThresholdShares = 65535
+
+var (
+	// ErrThresholdParams is returned when K & N are not 1 <= K <= N <= 65535.
+	ErrThresholdParams = errors.New("threshold requires 1 <= K <= N <= 65535")
+
+	// ErrNotEnoughShares is returned by Open when fewer than K shares are
+	// present.
+	ErrNotEnoughShares = errors.New("not enough shares")
+
+	// ErrInvalidShare is returned by Open for a malformed share, or shares of
+	// different lengths.
+	ErrInvalidShare = errors.New("invalid share")
+)
+
+// NewThreshold constructs a new Threshold for N shares of which K are required
+// to recover the secrets.
+func NewThreshold(n, k uint32, comment string) *Threshold {
+	return &Threshold{
+		N:       n,
+		K:       k,
+		comment: comment,
+	}
+}
+
+// Comment string
+func (c *Threshold) Comment() string {
+	return c.comment
+}
+
+// Close seals the secret to the inputs with Shamir's Secret Sharing over
+// GF(2^16). Each share is a padding flag byte followed by the 16-bit share
+// symbols.
+func (c *Threshold) Close(inputs, secrets [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) < int(c.K) || len(inputs) > int(c.N) {
+		return fmt.Errorf("between %d and %d inputs required", c.K, c.N)
+	}
+	if len(secrets) != 1 {
+		return errors.New("Threshold supports only a single secret")
+	}
+
+	secret, pad := secrets[0], byte(len(secrets[0])%2)
+	if pad == 1 {
+		secret = append(append([]byte{}, secret...), 0)
+	}
+
+	for i := range inputs {
+		inputs[i] = make([]byte, 1, 1+len(secret))
+		inputs[i][0] = pad
+	}
+
+	coeffs, buf := make([]uint16, c.K), make([]byte, 2*(c.K-1))
+	for j := 0; j < len(secret); j += 2 {
+		// f(x) = s + a_1*x + ... + a_(K-1)*x^(K-1)
+		coeffs[0] = uint16(secret[j])<<8 | uint16(secret[j+1])
+		if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+			return err
+		}
+		for m := 1; m < len(coeffs); m++ {
+			coeffs[m] = uint16(buf[2*m-2])<<8 | uint16(buf[2*m-1])
+		}
+
+		for i := range inputs {
+			x, y := uint16(i+1), uint16(0)
+			for m := len(coeffs) - 1; m >= 0; m-- {
+				y = gf16Mul(y, x) ^ coeffs[m]
+			}
+			inputs[i] = append(inputs[i], byte(y>>8), byte(y))
+		}
+	}
+	return nil
+}
+
+// Open unseals the secret from the inputs, of which K are required. Missing
+// inputs are nil or empty.
+func (c *Threshold) Open(secrets, inputs [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) > int(c.N) {
+		return errors.New("Too many inputs")
+	}
+	if len(secrets) != 1 {
+		return errors.New("Too many secrets expected")
+	}
+
+	var (
+		xs    []uint16
+		share []byte
+	)
+	for i, v := range inputs {
+		if len(v) == 0 {
+			continue
+		}
+		if share == nil {
+			share = v
+		}
+		if len(v)%2 != 1 || v[0] > 1 || len(v) != len(share) || v[0] != share[0] {
+			return ErrInvalidShare
+		}
+		if xs = append(xs, uint16(i+1)); len(xs) == int(c.K) {
+			break
+		}
+	}
+	if len(xs) < int(c.K) {
+		return ErrNotEnoughShares
+	}
+
+	// lagrange basis polynomials at x = 0
+	basis := make([]uint16, len(xs))
+	for i, xi := range xs {
+		num, den := uint16(1), uint16(1)
+		for _, xj := range xs {
+			if xj != xi {
+				num = gf16Mul(num, xj)
+				den = gf16Mul(den, xj^xi)
+			}
+		}
+		basis[i] = gf16Div(num, den)
+	}
+
+	secret := make([]byte, 0, len(share)-1)
+	for j := 1; j < len(share); j += 2 {
+		s := uint16(0)
+		for i, x := range xs {
+			v := inputs[x-1]
+			s ^= gf16Mul(uint16(v[j])<<8|uint16(v[j+1]), basis[i])
+		}
+		secret = append(secret, byte(s>>8), byte(s))
+	}
+
+	if share[0] == 1 {
+		if len(secret) == 0 {
+			return ErrInvalidShare
+		}
+		secret = secret[:len(secret)-1]
+	}
+
+	secrets[0] = secret
+	return nil
+}
+
+func (c *Threshold) validate() error {
+	if c.K < 1 || c.K > c.N || c.N > MaxThresholdShares {
+		return ErrThresholdParams
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package cryptex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestThreshold(t *testing.T) {
+	tests := []struct {
+		n, k   uint32
+		secret string
+	}{
+		{1, 1, "super secret password"},
+		{2, 2, "super secret password"},
+		{5, 1, "super secret passwords"},
+		{11, 7, "super secret password"},
+		{300, 260, "super secret passwords"},
+	}
+
+	for _, test := range tests {
+		want := [][]byte{[]byte(test.secret)}
+		cptx := NewThreshold(test.n, test.k, "Threshold cryptex")
+
+		inputs := make([][]byte, test.n)
+		if err := cptx.Close(inputs, want); err != nil {
+			t.Fatal(err)
+		}
+
+		got := make([][]byte, len(want))
+		if err := cptx.Open(got, inputs); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("want secret %q, got %q", want, got)
+		}
+
+		// drop the first N-K shares
+		partial := make([][]byte, len(inputs))
+		copy(partial[test.n-test.k:], inputs[test.n-test.k:])
+		if err := cptx.Open(got, partial); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("want secret %q, got %q", want, got)
+		}
+
+		partial[test.n-1] = []byte{}
+		if err := cptx.Open(got, partial); err != ErrNotEnoughShares {
+			t.Errorf("want ErrNotEnoughShares for %d-of-%d, got %v", test.k, test.n, err)
+		}
+	}
+}
+
+func TestThresholdErrors(t *testing.T) {
+	for _, nk := range [][2]uint32{{0, 0}, {3, 0}, {3, 4}, {65536, 2}} {
+		cptx := NewThreshold(nk[0], nk[1], "")
+		if err := cptx.Close(make([][]byte, 1), [][]byte{[]byte("secret")}); err != ErrThresholdParams {
+			t.Errorf("want ErrThresholdParams for %d-of-%d, got %v", nk[1], nk[0], err)
+		}
+	}
+
+	cptx := NewThreshold(3, 2, "")
+	inputs := make([][]byte, 3)
+	if err := cptx.Close(inputs, [][]byte{[]byte("secret")}); err != nil {
+		t.Fatal(err)
+	}
+
+	inputs[1] = inputs[1][:len(inputs[1])-2]
+	if err := cptx.Open(make([][]byte, 1), inputs); err != ErrInvalidShare {
+		t.Errorf("want ErrInvalidShare, got %v", err)
+	}
+}
+
+func TestGF16(t *testing.T) {
+	seen := make(map[uint16]bool, 65535)
+	for _, v := range gf16Exp[:65535] {
+		seen[v] = true
+	}
+	if len(seen) != 65535 || seen[0] {
+		t.Fatalf("gf16 generator order is %d, want 65535", len(seen))
+	}
+
+	for _, a := range []uint16{1, 2, 0x1234, 0xffff} {
+		for _, b := range []uint16{1, 3, 0x8000, 0xfffe} {
+			if got := gf16Div(gf16Mul(a, b), b); got != a {
+				t.Errorf("want (%#x*%#x)/%#x = %#x, got %#x", a, b, b, a, got)
+			}
+		}
+	}
+}
+
+func TestRoundTripThreshold(t *testing.T) {
+	want := NewThreshold(1000, 1, "Threshold cryptex")
+
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *want != *got.(*Threshold) {
+		t.Errorf("want Threshold cryptex %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,228 @@
+package cryptex
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+const (
+	// DefaultTimeLockBits is the default TimeLock modulus size.
+	DefaultTimeLockBits = 2048
+
+	timeLockInfo = "vcrypt timelock"
+
+	// squarings between context checks & progress reports
+	timeLockStep = 1 << 14
+)
+
+var big2 = big.NewInt(2)
+
+// NewTimeLock constructs a new TimeLock requiring iterations sequential
+// squarings modulo a bits sized RSA modulus to open.
+func NewTimeLock(iterations uint64, bits uint32, comment string) *TimeLock {
+	return &TimeLock{
+		Iterations: iterations,
+		Bits:       bits,
+		comment:    comment,
+	}
+}
+
+// CalibrateTimeLock returns the number of squarings modulo a bits sized
+// modulus this machine performs in d.
+func CalibrateTimeLock(d time.Duration, bits uint32) (uint64, error) {
+	if bits < 512 {
+		return 0, errors.New("TimeLock modulus must be at least 512 bits")
+	}
+
+	n, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+	if err != nil {
+		return 0, err
+	}
+	n.SetBit(n, int(bits)-1, 1)
+	n.SetBit(n, 0, 1)
+
+	x, err := rand.Int(rand.Reader, n)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		count uint64
+		start = time.Now()
+	)
+	for time.Since(start) < 250*time.Millisecond {
+		for i := 0; i < 1024; i++ {
+			x.Mul(x, x).Mod(x, n)
+		}
+		count += 1024
+	}
+
+	return uint64(float64(count) * d.Seconds() / time.Since(start).Seconds()), nil
+}
+
+// Comment string
+func (c *TimeLock) Comment() string {
+	return c.comment
+}
+
+// Close seals the secret in a Rivest-Shamir-Wagner time-lock puzzle. A fresh
+// modulus is generated & its factors are used to compute base^(2^iterations)
+// without the sequential squarings. The secret is sealed with a secretbox key
+// derived from the result & the puzzle is the single input.
+func (c *TimeLock) Close(inputs, secrets [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) != 1 {
+		return errors.New("TimeLock supports exactly 1 input")
+	}
+	if len(secrets) != 1 {
+		return errors.New("TimeLock supports only a single secret")
+	}
+
+	p, err := rand.Prime(rand.Reader, int(c.Bits)/2)
+	if err != nil {
+		return err
+	}
+	q, err := rand.Prime(rand.Reader, int(c.Bits)-int(c.Bits)/2)
+	if err != nil {
+		return err
+	}
+
+	one := big.NewInt(1)
+	n := new(big.Int).Mul(p, q)
+	phi := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+
+	// base in [2, n-2]
+	base, err := rand.Int(rand.Reader, new(big.Int).Sub(n, big.NewInt(3)))
+	if err != nil {
+		return err
+	}
+	base.Add(base, big2)
+
+	// base^(2^t) = base^(2^t mod phi(n)) mod n
+	e := new(big.Int).Exp(big2, new(big.Int).SetUint64(c.Iterations), phi)
+	key, err := timeLockKey(new(big.Int).Exp(base, e, n), n)
+	if err != nil {
+		return err
+	}
+
+	nonce := [24]byte{}
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return err
+	}
+
+	puzzle := &TimeLockPuzzle{
+		Modulus: n.Bytes(),
+		Base:    base.Bytes(),
+		Box:     secretbox.Seal(nonce[:], secrets[0], &nonce, key),
+	}
+
+	data, err := puzzle.Marshal()
+	if err != nil {
+		return err
+	}
+
+	inputs[0] = data
+	return nil
+}
+
+// Open unseals the secret by solving the time-lock puzzle input with
+// sequential squarings.
+func (c *TimeLock) Open(secrets, inputs [][]byte) error {
+	return c.OpenContext(context.Background(), nil, secrets, inputs)
+}
+
+// OpenContext unseals the secret by solving the time-lock puzzle input with
+// sequential squarings, checking ctx & reporting progress between steps.
+func (c *TimeLock) OpenContext(ctx context.Context, progress func(done, total uint64), secrets, inputs [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) != 1 {
+		return errors.New("TimeLock supports exactly 1 input")
+	}
+	if len(secrets) != 1 {
+		return errors.New("Too many secrets expected")
+	}
+
+	puzzle := &TimeLockPuzzle{}
+	if err := puzzle.Unmarshal(inputs[0]); err != nil {
+		return err
+	}
+	if len(puzzle.Box) < 24+secretbox.Overhead {
+		return errors.New("invalid TimeLock puzzle")
+	}
+
+	n := new(big.Int).SetBytes(puzzle.Modulus)
+	x := new(big.Int).SetBytes(puzzle.Base)
+	if n.Sign() == 0 || x.Cmp(n) >= 0 {
+		return errors.New("invalid TimeLock puzzle")
+	}
+
+	for done := uint64(0); done < c.Iterations; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(done, c.Iterations)
+		}
+
+		step := c.Iterations - done
+		if step > timeLockStep {
+			step = timeLockStep
+		}
+		for i := uint64(0); i < step; i++ {
+			x.Mul(x, x).Mod(x, n)
+		}
+		done += step
+	}
+	if progress != nil {
+		progress(c.Iterations, c.Iterations)
+	}
+
+	key, err := timeLockKey(x, n)
+	if err != nil {
+		return err
+	}
+
+	nonce := [24]byte{}
+	copy(nonce[:], puzzle.Box)
+
+	secret, ok := secretbox.Open(nil, puzzle.Box[24:], &nonce, key)
+	if !ok {
+		return errors.New("decryption failure")
+	}
+
+	secrets[0] = secret
+	return nil
+}
+
+func (c *TimeLock) validate() error {
+	if c.Iterations < 1 {
+		return errors.New("iterations must be > 0")
+	}
+	if c.Bits < 512 {
+		return errors.New("TimeLock modulus must be at least 512 bits")
+	}
+	return nil
+}
+
+func timeLockKey(x, n *big.Int) (*[32]byte, error) {
+	buf := x.FillBytes(make([]byte, (n.BitLen()+7)/8))
+	kdf := hkdf.New(sha256.New, buf, n.Bytes(), []byte(timeLockInfo))
+
+	key := [32]byte{}
+	if _, err := io.ReadFull(kdf, key[:]); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -0,0 +1,88 @@
+package cryptex
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimeLock(t *testing.T) {
+	want := [][]byte{[]byte("super secret password")}
+	cptx := NewTimeLock(50000, 512, "TimeLock cryptex")
+
+	inputs := make([][]byte, 1)
+	if err := cptx.Close(inputs, want); err != nil {
+		t.Fatal(err)
+	}
+
+	var reports []uint64
+	progress := func(done, total uint64) {
+		if total != cptx.Iterations {
+			t.Errorf("want total %d, got %d", cptx.Iterations, total)
+		}
+		reports = append(reports, done)
+	}
+
+	got := make([][]byte, len(want))
+	if err := cptx.OpenContext(context.Background(), progress, got, inputs); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want secret %q, got %q", want, got)
+	}
+
+	if len(reports) < 2 || reports[0] != 0 || reports[len(reports)-1] != cptx.Iterations {
+		t.Errorf("want progress from 0 to %d, got %v", cptx.Iterations, reports)
+	}
+
+	// fewer squarings yield the wrong key
+	short := NewTimeLock(cptx.Iterations-1, 512, "")
+	if err := short.Open(got, inputs); err == nil {
+		t.Error("want decryption failure for too few iterations")
+	}
+}
+
+func TestTimeLockCancel(t *testing.T) {
+	cptx := NewTimeLock(1<<40, 512, "TimeLock cryptex")
+
+	inputs := make([][]byte, 1)
+	if err := cptx.Close(inputs, [][]byte{[]byte("secret")}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := cptx.OpenContext(ctx, nil, make([][]byte, 1), inputs); err != context.DeadlineExceeded {
+		t.Errorf("want context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestCalibrateTimeLock(t *testing.T) {
+	n, err := CalibrateTimeLock(time.Second, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n == 0 {
+		t.Error("want non-zero iterations per second")
+	}
+}
+
+func TestRoundTripTimeLock(t *testing.T) {
+	want := NewTimeLock(1000000, DefaultTimeLockBits, "TimeLock cryptex")
+
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *want != *got.(*TimeLock) {
+		t.Errorf("want TimeLock cryptex %v, got %v", want, got)
+	}
+}
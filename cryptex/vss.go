@@ -0,0 +1,283 @@
+package cryptex
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"filippo.io/edwards25519"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+const vssInfo = "vcrypt vss edwards25519"
+
+// VSSError is returned by Open when too few of the shares are valid.
+type VSSError struct {
+	K       int
+	Valid   int
+	Invalid []int // input indexes of the invalid shares
+}
+
+func (e *VSSError) Error() string {
+	return fmt.Sprintf("vss requires %d valid shares, have %d: invalid inputs %v", e.K, e.Valid, e.Invalid)
+}
+
+// NewVSS constructs a new VSS for N shares of which K are required to recover
+// the secrets.
+func NewVSS(n, k uint32, comment string) *VSS {
+	return &VSS{
+		N:       n,
+		K:       k,
+		comment: comment,
+	}
+}
+
+// Comment string
+func (c *VSS) Comment() string {
+	return c.comment
+}
+
+// Close seals the secret to the share inputs with Feldman verifiable secret
+// sharing over the edwards25519 group. A random scalar is split into the
+// shares & the secret is sealed with a secretbox key derived from it. The
+// final input is the material for the share commitments & secretbox.
+func (c *VSS) Close(inputs, secrets [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) <= int(c.K) || len(inputs) > int(c.N)+1 {
+		return fmt.Errorf("between %d and %d inputs required", c.K+1, c.N+1)
+	}
+	if len(secrets) != 1 {
+		return errors.New("VSS supports only a single secret")
+	}
+
+	coeffs := make([]*edwards25519.Scalar, c.K)
+	for i := range coeffs {
+		buf := make([]byte, 64)
+		if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+			return err
+		}
+
+		var err error
+		if coeffs[i], err = edwards25519.NewScalar().SetUniformBytes(buf); err != nil {
+			return err
+		}
+	}
+
+	cmts := make([]byte, 0, 32*len(coeffs))
+	for _, a := range coeffs {
+		cmts = append(cmts, new(edwards25519.Point).ScalarBaseMult(a).Bytes()...)
+	}
+
+	nshares := len(inputs) - 1
+	for i := 0; i < nshares; i++ {
+		// f(x) = a_0 + a_1*x + ... + a_(K-1)*x^(K-1)
+		x, y := vssIndex(i), edwards25519.NewScalar()
+		for j := len(coeffs) - 1; j >= 0; j-- {
+			y.MultiplyAdd(y, x, coeffs[j])
+		}
+		inputs[i] = y.Bytes()
+	}
+
+	key, err := vssKey(coeffs[0], cmts)
+	if err != nil {
+		return err
+	}
+
+	nonce := [24]byte{}
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return err
+	}
+
+	out := append(cmts, nonce[:]...)
+	inputs[nshares] = secretbox.Seal(out, secrets[0], &nonce, key)
+	return nil
+}
+
+// Open unseals the secret from the share inputs, of which K valid shares are
+// required. Each share is checked against the commitments & invalid shares
+// are left out of the recombination.
+func (c *VSS) Open(secrets, inputs [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) <= int(c.K) || len(inputs) > int(c.N)+1 {
+		return errors.New("Wrong number of inputs")
+	}
+	if len(secrets) != 1 {
+		return errors.New("Too many secrets expected")
+	}
+
+	shares, invalid, err := c.verify(inputs)
+	if err != nil {
+		return err
+	}
+	if len(shares) < int(c.K) {
+		return &VSSError{
+			K:       int(c.K),
+			Valid:   len(shares),
+			Invalid: invalid,
+		}
+	}
+
+	// lagrange interpolation of f(0) with the first K valid shares
+	idxs := make([]int, 0, c.K)
+	for i := range inputs {
+		if _, ok := shares[i]; ok && len(idxs) < int(c.K) {
+			idxs = append(idxs, i)
+		}
+	}
+
+	s := edwards25519.NewScalar()
+	for _, i := range idxs {
+		num, den := vssScalar(1), vssScalar(1)
+		for _, m := range idxs {
+			if m == i {
+				continue
+			}
+
+			num.Multiply(num, vssIndex(m))
+			den.Multiply(den, edwards25519.NewScalar().Subtract(vssIndex(m), vssIndex(i)))
+		}
+
+		coeff := num.Multiply(num, den.Invert(den))
+		s.MultiplyAdd(shares[i], coeff, s)
+	}
+
+	mtrl := inputs[len(inputs)-1]
+	cmts := mtrl[:32*c.K]
+	if new(edwards25519.Point).ScalarBaseMult(s).Equal(vssCommitment(cmts, 0)) != 1 {
+		return errors.New("recovered secret does not match commitment")
+	}
+
+	key, err := vssKey(s, cmts)
+	if err != nil {
+		return err
+	}
+
+	nonce := [24]byte{}
+	copy(nonce[:], mtrl[32*c.K:])
+
+	secret, ok := secretbox.Open(nil, mtrl[32*c.K+24:], &nonce, key)
+	if !ok {
+		return errors.New("decryption failure")
+	}
+
+	secrets[0] = secret
+	return nil
+}
+
+// Verify checks the share inputs against the commitments in the final input
+// and returns the input indexes of the invalid shares. Missing shares are not
+// invalid.
+func (c *VSS) Verify(inputs [][]byte) ([]int, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	if len(inputs) <= int(c.K) || len(inputs) > int(c.N)+1 {
+		return nil, errors.New("Wrong number of inputs")
+	}
+
+	_, invalid, err := c.verify(inputs)
+	return invalid, err
+}
+
+// verify returns the valid shares by input index & the input indexes of the
+// invalid shares.
+func (c *VSS) verify(inputs [][]byte) (map[int]*edwards25519.Scalar, []int, error) {
+	mtrl := inputs[len(inputs)-1]
+	if len(mtrl) < 32*int(c.K)+24+secretbox.Overhead {
+		return nil, nil, errors.New("invalid vss commitments")
+	}
+
+	cmts := make([]*edwards25519.Point, c.K)
+	for j := range cmts {
+		if cmts[j] = vssCommitment(mtrl, j); cmts[j] == nil {
+			return nil, nil, errors.New("invalid vss commitment")
+		}
+	}
+
+	var (
+		shares  = make(map[int]*edwards25519.Scalar, len(inputs)-1)
+		invalid []int
+	)
+	for i, v := range inputs[:len(inputs)-1] {
+		if len(v) == 0 {
+			continue
+		}
+
+		y, err := edwards25519.NewScalar().SetCanonicalBytes(v)
+		if err != nil {
+			invalid = append(invalid, i)
+			continue
+		}
+
+		// y*B == C_0 + x*C_1 + ... + x^(K-1)*C_(K-1)
+		x, xj := vssIndex(i), vssScalar(1)
+		scalars := make([]*edwards25519.Scalar, len(cmts))
+		for j := range cmts {
+			scalars[j] = edwards25519.NewScalar().Set(xj)
+			xj.Multiply(xj, x)
+		}
+
+		want := new(edwards25519.Point).VarTimeMultiScalarMult(scalars, cmts)
+		if new(edwards25519.Point).ScalarBaseMult(y).Equal(want) != 1 {
+			invalid = append(invalid, i)
+			continue
+		}
+
+		shares[i] = y
+	}
+	return shares, invalid, nil
+}
+
+func (c *VSS) validate() error {
+	if c.N <= 2 {
+		return errors.New("N must be > 2")
+	}
+	if c.K <= 1 {
+		return errors.New("K must be > 1")
+	}
+	if c.K >= c.N {
+		return errors.New("N must be > K")
+	}
+	return nil
+}
+
+// vssIndex returns the share x coordinate of input i, which is i+1.
+func vssIndex(i int) *edwards25519.Scalar {
+	return vssScalar(uint32(i + 1))
+}
+
+func vssScalar(x uint32) *edwards25519.Scalar {
+	buf := make([]byte, 32)
+	buf[0], buf[1], buf[2], buf[3] = byte(x), byte(x>>8), byte(x>>16), byte(x>>24)
+
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(buf)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func vssCommitment(cmts []byte, j int) *edwards25519.Point {
+	p, err := new(edwards25519.Point).SetBytes(cmts[32*j : 32*(j+1)])
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+func vssKey(s *edwards25519.Scalar, cmts []byte) (*[32]byte, error) {
+	kdf := hkdf.New(sha256.New, s.Bytes(), cmts, []byte(vssInfo))
+
+	key := [32]byte{}
+	if _, err := io.ReadFull(kdf, key[:]); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
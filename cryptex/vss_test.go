@@ -0,0 +1,98 @@
+package cryptex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVSS(t *testing.T) {
+	want := [][]byte{[]byte("super secret password")}
+	cptx := NewVSS(5, 3, "VSS cryptex")
+
+	inputs := make([][]byte, 6)
+	if err := cptx.Close(inputs, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([][]byte, len(want))
+	if err := cptx.Open(got, inputs); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want secret %q, got %q", want, got)
+	}
+
+	partial := [][]byte{nil, inputs[1], nil, inputs[3], inputs[4], inputs[5]}
+	if err := cptx.Open(got, partial); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want secret %q, got %q", want, got)
+	}
+
+	if err := cptx.Open(got, [][]byte{nil, nil, inputs[2], inputs[3], nil, inputs[5]}); err == nil {
+		t.Errorf("vss cryptex opened with too few inputs")
+	}
+}
+
+func TestVSSInvalidShares(t *testing.T) {
+	want := [][]byte{[]byte("super secret password")}
+	cptx := NewVSS(5, 3, "VSS cryptex")
+
+	inputs := make([][]byte, 6)
+	if err := cptx.Close(inputs, want); err != nil {
+		t.Fatal(err)
+	}
+
+	// swapped shares are well formed but fail the commitment check
+	inputs[0], inputs[2] = inputs[2], inputs[0]
+	inputs[4] = []byte("garbage")
+
+	invalid, err := cptx.Verify(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0, 2, 4}; !reflect.DeepEqual(want, invalid) {
+		t.Errorf("want invalid inputs %v, got %v", want, invalid)
+	}
+
+	got := make([][]byte, len(want))
+	err = cptx.Open(got, inputs)
+	verr, ok := err.(*VSSError)
+	if !ok {
+		t.Fatalf("want *VSSError, got %v", err)
+	}
+	if want := []int{0, 2, 4}; !reflect.DeepEqual(want, verr.Invalid) {
+		t.Errorf("want invalid inputs %v, got %v", want, verr.Invalid)
+	}
+	if verr.Valid != 2 {
+		t.Errorf("want 2 valid shares, got %d", verr.Valid)
+	}
+
+	// recombine without the bad share once K valid shares remain
+	inputs[0], inputs[2] = inputs[2], inputs[0]
+	if err := cptx.Open(got, inputs); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want secret %q, got %q", want, got)
+	}
+}
+
+func TestRoundTripVSS(t *testing.T) {
+	want := NewVSS(7, 5, "VSS cryptex")
+
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *want != *got.(*VSS) {
+		t.Errorf("want VSS cryptex %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,139 @@
+package cryptex
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/codahale/sss"
+)
+
+// NewWeightedSSS constructs a new WeightedSSS where each input counts for its
+// weight & inputs totaling the threshold weight are required to recover the
+// secrets.
+func NewWeightedSSS(weights []uint32, threshold uint32, comment string) *WeightedSSS {
+	return &WeightedSSS{
+		Weights:   weights,
+		Threshold: threshold,
+		comment:   comment,
+	}
+}
+
+// Comment string
+func (c *WeightedSSS) Comment() string {
+	return c.comment
+}
+
+// Weighting describes the threshold & input weights.
+func (c *WeightedSSS) Weighting() string {
+	weights := make([]string, 0, len(c.Weights))
+	for _, w := range c.Weights {
+		weights = append(weights, fmt.Sprint(w))
+	}
+	return fmt.Sprintf("weight %d of %s", c.Threshold, strings.Join(weights, ","))
+}
+
+// Close seals the secret to the inputs. The secret is split into as many
+// shares as the total weight & each input is the concatenation of as many
+// shares as its weight.
+func (c *WeightedSSS) Close(inputs, secrets [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) != len(c.Weights) {
+		return fmt.Errorf("%d inputs required", len(c.Weights))
+	}
+	if len(secrets) != 1 {
+		return errors.New("WeightedSSS supports only a single secret")
+	}
+
+	shares, err := sss.Split(byte(c.total()), byte(c.Threshold), secrets[0])
+	if err != nil {
+		return err
+	}
+
+	x := byte(1)
+	for i, w := range c.Weights {
+		input := make([]byte, 0, int(w)*len(secrets[0]))
+		for j := uint32(0); j < w; j++ {
+			input = append(input, shares[x]...)
+			x++
+		}
+		inputs[i] = input
+	}
+	return nil
+}
+
+// Open unseals the secret from the inputs, of which inputs with a combined
+// weight of at least the threshold are required.
+func (c *WeightedSSS) Open(secrets, inputs [][]byte) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	if len(inputs) != len(c.Weights) {
+		return errors.New("Wrong number of inputs")
+	}
+	if len(secrets) != 1 {
+		return errors.New("Too many secrets expected")
+	}
+
+	var (
+		shares = make(map[byte][]byte, c.total())
+		weight uint32
+		size   = -1
+		x      = byte(1)
+	)
+	for i, w := range c.Weights {
+		input := inputs[i]
+		if len(input) == 0 {
+			x += byte(w)
+			continue
+		}
+
+		if len(input)%int(w) != 0 || (size != -1 && len(input)/int(w) != size) {
+			return fmt.Errorf("invalid shares for input %d", i)
+		}
+		size = len(input) / int(w)
+
+		for j := 0; j < int(w); j++ {
+			shares[x] = input[j*size : (j+1)*size]
+			x++
+		}
+		weight += w
+	}
+	if weight < c.Threshold {
+		return fmt.Errorf("Not enough inputs: weight %d of %d", weight, c.Threshold)
+	}
+
+	secrets[0] = sss.Combine(shares)
+	return nil
+}
+
+func (c *WeightedSSS) total() uint32 {
+	var total uint32
+	for _, w := range c.Weights {
+		total += w
+	}
+	return total
+}
+
+func (c *WeightedSSS) validate() error {
+	if len(c.Weights) < 2 {
+		return errors.New("at least 2 weights required")
+	}
+	for _, w := range c.Weights {
+		if w < 1 || w > 255 {
+			return errors.New("weights must be between 1 and 255")
+		}
+	}
+	if c.total() > 255 {
+		return errors.New("total weight must be < 256")
+	}
+	if c.Threshold <= 1 {
+		return errors.New("threshold must be > 1")
+	}
+	if c.Threshold > c.total() {
+		return errors.New("threshold must not exceed the total weight")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package cryptex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWeightedSSS(t *testing.T) {
+	want := [][]byte{[]byte("super secret password")}
+	cptx := NewWeightedSSS([]uint32{2, 1, 1, 1}, 4, "WeightedSSS cryptex")
+
+	inputs := make([][]byte, 4)
+	if err := cptx.Close(inputs, want); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2*len(want[0]), len(inputs[0]); want != got {
+		t.Errorf("want weighted input len %d, got %d", want, got)
+	}
+
+	got := make([][]byte, len(want))
+	if err := cptx.Open(got, inputs); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want secret %q, got %q", want, got)
+	}
+
+	// 2 + 1 + 1
+	if err := cptx.Open(got, [][]byte{inputs[0], nil, inputs[2], inputs[3]}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want secret %q, got %q", want, got)
+	}
+
+	// 1 + 1 + 1
+	if err := cptx.Open(got, [][]byte{nil, inputs[1], inputs[2], inputs[3]}); err == nil {
+		t.Errorf("weighted sss cryptex opened with too little weight")
+	}
+}
+
+func TestRoundTripWeightedSSS(t *testing.T) {
+	want := NewWeightedSSS([]uint32{3, 1, 2}, 4, "WeightedSSS cryptex")
+
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got.(*WeightedSSS)) {
+		t.Errorf("want WeightedSSS cryptex %v, got %v", want, got)
+	}
+}
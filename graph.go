@@ -2,8 +2,10 @@ package vcrypt
 
 import (
 	"bytes"
+	"container/list"
 	"crypto/rand"
 	"errors"
+	"sort"
 
 	"github.com/vcrypt/vcrypt/cryptex"
 	"github.com/vcrypt/vcrypt/graph"
@@ -148,6 +150,137 @@ func (g *Graph) Nodes() ([]*Node, error) {
 	return nodes, nil
 }
 
+// vertex returns the cryptex vertex for the node digest or a unique prefix of
+// the digest.
+func (g *Graph) vertex(id []byte) (*graph.Vertex, error) {
+	if len(id) == 0 {
+		return nil, errors.New("missing node id")
+	}
+
+	var vrt *graph.Vertex
+	for v, fp := range g.digests {
+		if !bytes.HasPrefix(fp, id) {
+			continue
+		}
+		if vrt != nil {
+			return nil, errors.New("ambiguous node id")
+		}
+		vrt = v
+	}
+
+	if vrt == nil {
+		return nil, errors.New("node not found")
+	}
+	if _, ok := vrt.Value.(*cryptex.Envelope); !ok {
+		return nil, errors.New("node is not a cryptex")
+	}
+	return vrt, nil
+}
+
+// graft replaces the vertex & its edges with the root & vertices of sub. The
+// replaced subgraph is unreachable unless shared with other vertices.
+func (g *Graph) graft(vrt *graph.Vertex, sub *Graph) error {
+	verts := map[*graph.Vertex]*graph.Vertex{sub.Root: vrt}
+
+	vrt.Value = sub.Root.Value
+	g.Adjacency[vrt] = list.New()
+	g.nonces[vrt] = sub.nonces[sub.Root]
+	delete(g.digests, vrt)
+
+	for v := range sub.Adjacency {
+		if v != sub.Root {
+			verts[v] = g.DAG.Add(v.Value)
+			g.nonces[verts[v]] = sub.nonces[v]
+		}
+	}
+
+	for from, edges := range sub.Adjacency {
+		for e := edges.Front(); e != nil; e = e.Next() {
+			if err := g.AddEdge(verts[e.Value.(*graph.Vertex)], verts[from]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parents returns the vertex of each edge to v.
+func (g *Graph) parents(v *graph.Vertex) []*graph.Vertex {
+	var vs []*graph.Vertex
+	for from, edges := range g.Adjacency {
+		for e := edges.Front(); e != nil; e = e.Next() {
+			if e.Value.(*graph.Vertex) == v {
+				vs = append(vs, from)
+			}
+		}
+	}
+	return vs
+}
+
+// parentEdges returns the parent vertex of each edge to v, sorted by parent
+// digest. A parent with several edges to v is listed once per edge.
+func (g *Graph) parentEdges(v *graph.Vertex) ([]*graph.Vertex, error) {
+	parents := g.parents(v)
+	for _, p := range parents {
+		if _, ok := g.digests[p]; !ok {
+			return nil, errors.New("missing parent vertex digest")
+		}
+	}
+
+	sort.SliceStable(parents, func(i, j int) bool {
+		return bytes.Compare(g.digests[parents[i]], g.digests[parents[j]]) < 0
+	})
+	return parents, nil
+}
+
+// position returns the NodePosition of v. Parent IDs are in sorted order.
+func (g *Graph) position(v *graph.Vertex) (NodePosition, error) {
+	var pos NodePosition
+
+	node, err := g.node(v)
+	if err != nil {
+		return pos, err
+	}
+	if pos.ID, err = node.Digest(); err != nil {
+		return pos, err
+	}
+
+	seen := map[*graph.Vertex]bool{}
+	for _, p := range g.parents(v) {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		fp, ok := g.digests[p]
+		if !ok {
+			return pos, errors.New("missing parent vertex digest")
+		}
+		pos.Parents = append(pos.Parents, fp)
+	}
+	sort.Slice(pos.Parents, func(i, j int) bool {
+		return bytes.Compare(pos.Parents[i], pos.Parents[j]) < 0
+	})
+
+	depths := map[*graph.Vertex]int{g.Root: 0}
+	queue := []*graph.Vertex{g.Root}
+	for len(queue) > 0 && queue[0] != v {
+		from := queue[0]
+		queue = queue[1:]
+
+		for e := g.Adjacency[from].Front(); e != nil; e = e.Next() {
+			to := e.Value.(*graph.Vertex)
+			if _, ok := depths[to]; !ok {
+				depths[to] = depths[from] + 1
+				queue = append(queue, to)
+			}
+		}
+	}
+	pos.Depth = depths[v]
+
+	return pos, nil
+}
+
 func (g *Graph) node(v *graph.Vertex) (*Node, error) {
 	edgeList, ok := g.Adjacency[v]
 	if !ok {
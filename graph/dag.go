@@ -156,15 +156,18 @@ func (g *DAG) depth(v *Vertex) int {
 	return 1 + max
 }
 
-// BFS walks the graph in breadth-first order.
+// BFS walks the graph in breadth-first order. A vertex is walked after each
+// vertex with an edge to it.
 func (g *DAG) BFS(fn WalkFunc) error {
 	l := list.New()
 	l.PushBack(g.Root)
 
+	indegree := g.indegree()
+	walked := map[*Vertex]int{}
 	visited := map[*Vertex]bool{}
 	for e := l.Front(); e != nil; e = e.Next() {
 		v := e.Value.(*Vertex)
-		if visited[v] {
+		if visited[v] || walked[v] < indegree[v] {
 			continue
 		}
 
@@ -173,11 +176,28 @@ func (g *DAG) BFS(fn WalkFunc) error {
 		}
 		visited[v] = true
 
+		g.walk(v, func(v *Vertex) error {
+			walked[v]++
+			return nil
+		})
 		l.PushBackList(g.Adjacency[v])
 	}
 	return nil
 }
 
+// indegree returns the number of edges to each vertex from the vertices
+// reachable from the root.
+func (g *DAG) indegree() map[*Vertex]int {
+	indegree := make(map[*Vertex]int, len(g.Adjacency))
+	g.DFS(func(v *Vertex) error {
+		return g.walk(v, func(v *Vertex) error {
+			indegree[v]++
+			return nil
+		})
+	})
+	return indegree
+}
+
 // DFS walks the graph in depth-first order.
 func (g *DAG) DFS(fn WalkFunc) error {
 	return g.dfs(g.Root, make(map[*Vertex]bool, len(g.Adjacency)), fn)
@@ -206,7 +226,12 @@ func (g *DAG) dfs(v *Vertex, visited map[*Vertex]bool, fn WalkFunc) error {
 
 // ReverseDFS walks the graph in reverse depth-first order.
 func (g *DAG) ReverseDFS(fn WalkFunc) error {
-	return g.rdfs(g.Root, make(map[*Vertex]bool, len(g.Adjacency)), fn)
+	return g.ReverseDFSFrom(g.Root, fn)
+}
+
+// ReverseDFSFrom walks the subgraph below v in reverse depth-first order.
+func (g *DAG) ReverseDFSFrom(v *Vertex, fn WalkFunc) error {
+	return g.rdfs(v, make(map[*Vertex]bool, len(g.Adjacency)), fn)
 }
 
 func (g *DAG) rdfs(v *Vertex, visited map[*Vertex]bool, fn WalkFunc) error {
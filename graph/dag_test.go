@@ -81,6 +81,20 @@ func TestDAG(t *testing.T) {
 			OrderDFS:  []string{"A", "B", "C", "D", "G", "E", "F"},
 			OrderRDFS: []string{"G", "D", "E", "F", "C", "B", "A"},
 		},
+		// uneven diamond:  A ---------> D
+		//                    |          |
+		//                    -> B -> C -/
+		{
+			Root: "A",
+			Adjacency: map[string][]string{
+				"A": []string{"D", "B"},
+				"B": []string{"C"},
+				"C": []string{"D"},
+			},
+			OrderBFS:  []string{"A", "B", "C", "D"},
+			OrderDFS:  []string{"A", "D", "B", "C"},
+			OrderRDFS: []string{"D", "C", "B", "A"},
+		},
 		// cycle error:  A <-> B
 		{
 			Root: "A",
@@ -25,9 +25,14 @@ func NewDetached() (*Detached, error) {
 }
 
 // Lock encrypts the data from r using the secretbox encryption scheme from
-// NaCl. The nonce & ciphertext are stored in the DB and the secret key is
-// returned.
+// NaCl. The nonce & ciphertext are written to the DB's payload Writer and the
+// secret key is returned.
 func (p *Detached) Lock(r io.Reader, db material.DB) ([]byte, error) {
+	w, err := payloadWriter(db)
+	if err != nil {
+		return nil, err
+	}
+
 	nonce, key := [24]byte{}, [32]byte{}
 	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
 		return nil, err
@@ -46,36 +51,33 @@ func (p *Detached) Lock(r io.Reader, db material.DB) ([]byte, error) {
 
 	secretbox.Seal(out[24:24], data, &nonce, &key)
 
-	hash := hmac.New(sha256.New, p.Nonce)
-
-	if _, err := io.Copy(hash, r); err != nil {
-		return nil, err
-	}
-	p.digest = hash.Sum(nil)
-
-	mtrl, err := material.New(p.digest, [][]byte{out})
-	if err != nil {
-		return nil, err
-	}
-
-	if err := db.StoreMaterial(mtrl); err != nil {
+	if _, err := w.Write(out); err != nil {
 		return nil, err
 	}
 
+	p.digest = digest(p.Nonce, out)
 	return key[:], nil
 }
 
-// Unlock decrypts the ciphertext retrieved from the DB using the secret key
-// and writes the cleartext to w.
+// Unlock reads the ciphertext from the DB's payload Reader, checks it against
+// the payload digest, then decrypts it using the secret key and writes the
+// cleartext to w.
 func (p *Detached) Unlock(w io.Writer, ks []byte, db material.DB) error {
-	mtrl, err := db.LoadMaterial(p.digest)
+	r, err := payloadReader(db)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadAll(r)
 	if err != nil {
 		return err
 	}
-	if mtrl == nil {
-		return errors.New("missing material for detached payload")
+	if !hmac.Equal(p.digest, digest(p.Nonce, data)) {
+		return errors.New("payload digest mismatch")
+	}
+	if len(data) < 24+secretbox.Overhead {
+		return errors.New("decryption failure")
 	}
-	data := mtrl.Data[0]
 
 	nonce, key := [24]byte{}, [32]byte{}
 	copy(nonce[:], data[:24])
@@ -94,5 +96,12 @@ func (p *Detached) Unlock(w io.Writer, ks []byte, db material.DB) error {
 
 // Digest is a unique series of bytes that identify the payload.
 func (p *Detached) Digest() ([]byte, error) {
+	// HMAC(Nonce, Ciphertext)
 	return p.digest, nil
 }
+
+func digest(nonce, data []byte) []byte {
+	hash := hmac.New(sha256.New, nonce)
+	hash.Write(data)
+	return hash.Sum(nil)
+}
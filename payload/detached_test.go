@@ -9,7 +9,7 @@ import (
 )
 
 func TestRoundTripDetached(t *testing.T) {
-	d := &db{}
+	d := &streamDB{}
 
 	want, err := NewDetached()
 	if err != nil {
@@ -43,6 +43,35 @@ func TestRoundTripDetached(t *testing.T) {
 	}
 }
 
+func TestDetachedDigest(t *testing.T) {
+	d1, d2 := &streamDB{}, &streamDB{}
+
+	pld, err := NewDetached()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := pld.Lock(bytes.NewBufferString("test data"), d1); err != nil {
+		t.Fatal(err)
+	}
+	fp1, _ := pld.Digest()
+
+	key, err := pld.Lock(bytes.NewBufferString("test data"), d2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp2, _ := pld.Digest()
+
+	if reflect.DeepEqual(fp1, fp2) {
+		t.Errorf("want distinct digests for distinct ciphertext, got %x", fp1)
+	}
+
+	// unlock with the ciphertext from the first lock
+	if err := pld.Unlock(new(bytes.Buffer), key, d1); err == nil {
+		t.Errorf("want digest mismatch error, got nil")
+	}
+}
+
 type db []*material.Material
 
 func (d *db) LoadMaterial(id []byte) (*material.Material, error) {
@@ -25,6 +25,20 @@ type Payload interface {
 	Unmarshal([]byte) error
 }
 
+// Sink is implemented by a material.DB that stores payload ciphertext outside
+// of the vault.
+type Sink interface {
+	// PayloadWriter returns the Writer for the payload ciphertext.
+	PayloadWriter() (io.Writer, error)
+}
+
+// Source is implemented by a material.DB that retrieves payload ciphertext
+// stored outside of the vault.
+type Source interface {
+	// PayloadReader returns the Reader for the payload ciphertext.
+	PayloadReader() (io.Reader, error)
+}
+
 // Wrap returns an intermediate form of the Payload for marshalling.
 func Wrap(p Payload) (*Envelope, error) {
 	env := &Envelope{}
@@ -61,3 +75,19 @@ func Unmarshal(data []byte) (Payload, error) {
 
 	return env.Payload()
 }
+
+func payloadWriter(db material.DB) (io.Writer, error) {
+	snk, ok := db.(Sink)
+	if !ok {
+		return nil, errors.New("DB does not support external payload ciphertext")
+	}
+	return snk.PayloadWriter()
+}
+
+func payloadReader(db material.DB) (io.Reader, error) {
+	src, ok := db.(Source)
+	if !ok {
+		return nil, errors.New("DB does not support external payload ciphertext")
+	}
+	return src.PayloadReader()
+}
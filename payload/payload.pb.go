@@ -9,6 +9,7 @@
 		payload/payload.proto
 		payload/attached.proto
 		payload/detached.proto
+		payload/stream.proto
 
 	It has these top-level messages:
 		Envelope
@@ -28,6 +29,7 @@ var _ = proto.Marshal
 type Envelope struct {
 	Attached *Attached `protobuf:"bytes,1,opt,name=attached" json:"attached,omitempty"`
 	Detached *Detached `protobuf:"bytes,2,opt,name=detached" json:"detached,omitempty"`
+	Stream   *Stream   `protobuf:"bytes,3,opt,name=stream" json:"stream,omitempty"`
 }
 
 func (m *Envelope) Reset()         { *m = Envelope{} }
@@ -48,6 +50,13 @@ func (m *Envelope) GetDetached() *Detached {
 	return nil
 }
 
+func (m *Envelope) GetStream() *Stream {
+	if m != nil {
+		return m.Stream
+	}
+	return nil
+}
+
 func (m *Envelope) Marshal() (data []byte, err error) {
 	size := m.Size()
 	data = make([]byte, size)
@@ -83,6 +92,16 @@ func (m *Envelope) MarshalTo(data []byte) (int, error) {
 		}
 		i += n2
 	}
+	if m.Stream != nil {
+		data[i] = 0x1a
+		i++
+		i = encodeVarintPayload(data, i, uint64(m.Stream.Size()))
+		n3, err := m.Stream.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
 	return i, nil
 }
 
@@ -124,6 +143,10 @@ func (m *Envelope) Size() (n int) {
 		l = m.Detached.Size()
 		n += 1 + l + sovPayload(uint64(l))
 	}
+	if m.Stream != nil {
+		l = m.Stream.Size()
+		n += 1 + l + sovPayload(uint64(l))
+	}
 	return n
 }
 
@@ -147,6 +170,9 @@ func (this *Envelope) GetValue() interface{} {
 	if this.Detached != nil {
 		return this.Detached
 	}
+	if this.Stream != nil {
+		return this.Stream
+	}
 	return nil
 }
 
@@ -156,6 +182,8 @@ func (this *Envelope) SetValue(value interface{}) bool {
 		this.Attached = vt
 	case *Detached:
 		this.Detached = vt
+	case *Stream:
+		this.Stream = vt
 	default:
 		return false
 	}
@@ -240,6 +268,36 @@ func (m *Envelope) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stream", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthPayload
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Stream == nil {
+				m.Stream = &Stream{}
+			}
+			if err := m.Stream.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			var sizeOfWire int
 			for {
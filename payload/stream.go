@@ -0,0 +1,185 @@
+package payload
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/vcrypt/vcrypt/material"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// DefaultSegmentSize is the cleartext size of a Stream segment.
+const DefaultSegmentSize = 64 * 1024
+
+// MaxSegmentSize is the largest Stream segment size. The segment buffers are
+// sized from the vault, so it is capped.
+const MaxSegmentSize = 16 * 1024 * 1024
+
+// NewStream constructs a Stream with the default segment size.
+func NewStream() (*Stream, error) {
+	nonce := make([]byte, 24)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, 19)
+	if _, err := io.ReadFull(rand.Reader, prefix); err != nil {
+		return nil, err
+	}
+
+	return &Stream{
+		Nonce:       nonce,
+		Prefix:      prefix,
+		SegmentSize: DefaultSegmentSize,
+	}, nil
+}
+
+// Lock encrypts the data from r in fixed size segments using the secretbox
+// encryption scheme from NaCl, and writes the ciphertext to the DB's payload
+// Writer. Each segment nonce is the Prefix, a segment counter & a final
+// segment flag, so that reordered, dropped, or truncated segments fail to
+// decrypt. The secret key is returned.
+func (p *Stream) Lock(r io.Reader, db material.DB) ([]byte, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	pw, err := payloadWriter(db)
+	if err != nil {
+		return nil, err
+	}
+
+	key := [32]byte{}
+	if _, err := io.ReadFull(rand.Reader, key[:]); err != nil {
+		return nil, err
+	}
+
+	hash := hmac.New(sha256.New, p.Nonce)
+	w := io.MultiWriter(pw, hash)
+
+	br := bufio.NewReader(r)
+	data := make([]byte, p.SegmentSize)
+	out := make([]byte, 0, len(data)+secretbox.Overhead)
+	for ctr := uint32(0); ; ctr++ {
+		final, n, err := readSegment(br, data)
+		if err != nil {
+			return nil, err
+		}
+
+		out = secretbox.Seal(out[:0], data[:n], p.segmentNonce(ctr, final), &key)
+		if _, err := w.Write(out); err != nil {
+			return nil, err
+		}
+
+		if final {
+			break
+		}
+		if ctr == math.MaxUint32 {
+			return nil, errors.New("too many payload segments")
+		}
+	}
+
+	p.digest = hash.Sum(nil)
+	return key[:], nil
+}
+
+// Unlock decrypts the ciphertext segments read from the DB's payload Reader
+// with the secret key and writes the cleartext to w as each segment is
+// authenticated. An error is returned for a corrupt or truncated stream, in
+// which case w has received the cleartext of the segments before it: the
+// cleartext is not complete or authentic until Unlock returns nil.
+func (p *Stream) Unlock(w io.Writer, ks []byte, db material.DB) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+
+	pr, err := payloadReader(db)
+	if err != nil {
+		return err
+	}
+
+	key := [32]byte{}
+	copy(key[:], ks[:])
+
+	hash := hmac.New(sha256.New, p.Nonce)
+	br := bufio.NewReader(io.TeeReader(pr, hash))
+
+	data := make([]byte, p.SegmentSize+secretbox.Overhead)
+	out := make([]byte, 0, p.SegmentSize)
+	for ctr := uint32(0); ; ctr++ {
+		final, n, err := readSegment(br, data)
+		if err != nil {
+			return err
+		}
+
+		var ok bool
+		if out, ok = secretbox.Open(out[:0], data[:n], p.segmentNonce(ctr, final), &key); !ok {
+			return errors.New("decryption failure")
+		}
+		if _, err := w.Write(out); err != nil {
+			return err
+		}
+
+		if final {
+			break
+		}
+		if ctr == math.MaxUint32 {
+			return errors.New("too many payload segments")
+		}
+	}
+
+	if !hmac.Equal(p.digest, hash.Sum(nil)) {
+		return errors.New("payload digest mismatch")
+	}
+	return nil
+}
+
+// Digest is a unique series of bytes that identify the payload.
+func (p *Stream) Digest() ([]byte, error) {
+	// HMAC(Nonce, Segments[*])
+	return p.digest, nil
+}
+
+func (p *Stream) segmentNonce(ctr uint32, final bool) *[24]byte {
+	nonce := [24]byte{}
+	copy(nonce[:19], p.Prefix)
+	binary.BigEndian.PutUint32(nonce[19:23], ctr)
+	if final {
+		nonce[23] = 1
+	}
+	return &nonce
+}
+
+func (p *Stream) validate() error {
+	if len(p.Prefix) != 19 {
+		return errors.New("invalid stream nonce prefix")
+	}
+	if p.SegmentSize == 0 || p.SegmentSize > MaxSegmentSize {
+		return errors.New("invalid stream segment size")
+	}
+	return nil
+}
+
+// readSegment fills data from r and reports if it is the final segment of
+// the stream.
+func readSegment(r *bufio.Reader, data []byte) (final bool, n int, err error) {
+	switch n, err = io.ReadFull(r, data); err {
+	case nil:
+		if _, err := r.Peek(1); err == io.EOF {
+			return true, n, nil
+		} else if err != nil {
+			return false, n, err
+		}
+		return false, n, nil
+	case io.EOF, io.ErrUnexpectedEOF:
+		return true, n, nil
+	default:
+		return false, n, err
+	}
+}
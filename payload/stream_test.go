@@ -0,0 +1,101 @@
+package payload
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRoundTripStream(t *testing.T) {
+	tests := []struct {
+		data string
+		size uint32
+	}{
+		{"", DefaultSegmentSize},
+		{"test data", DefaultSegmentSize},
+		{"test data", 3},
+		{"test data", 4},
+		{strings.Repeat("test data ", 1000), 100},
+	}
+
+	for _, tst := range tests {
+		d := &streamDB{}
+
+		want, err := NewStream()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want.SegmentSize = tst.size
+
+		key, err := want.Lock(bytes.NewBufferString(tst.data), d)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := want.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := &Stream{}
+		if err := got.Unmarshal(data); err != nil {
+			t.Fatal(err)
+		}
+
+		gbuf := new(bytes.Buffer)
+		if err := got.Unlock(gbuf, key, d); err != nil {
+			t.Fatal(err)
+		}
+
+		if gstr := gbuf.String(); tst.data != gstr {
+			t.Errorf("want Blob %q, got %q", tst.data, gstr)
+		}
+	}
+}
+
+func TestStreamTruncated(t *testing.T) {
+	d := &streamDB{}
+
+	pld, err := NewStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pld.SegmentSize = 4
+
+	key, err := pld.Lock(bytes.NewBufferString("test data"), d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// drop the final segment
+	d.Truncate(2 * (4 + 16))
+
+	if err := pld.Unlock(new(bytes.Buffer), key, d); err == nil {
+		t.Errorf("want error for truncated stream, got nil")
+	}
+}
+
+func TestStreamSegmentSize(t *testing.T) {
+	pld, err := NewStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pld.SegmentSize = MaxSegmentSize + 1
+
+	if _, err := pld.Lock(bytes.NewBufferString("test data"), &streamDB{}); err == nil {
+		t.Errorf("want error for oversized segment, got nil")
+	}
+	if err := pld.Unlock(new(bytes.Buffer), make([]byte, 32), &streamDB{}); err == nil {
+		t.Errorf("want error for oversized segment, got nil")
+	}
+}
+
+type streamDB struct {
+	db
+	bytes.Buffer
+}
+
+func (d *streamDB) PayloadWriter() (io.Writer, error) { return &d.Buffer, nil }
+
+func (d *streamDB) PayloadReader() (io.Reader, error) { return &d.Buffer, nil }
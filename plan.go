@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"io"
+	"sort"
 
 	"github.com/vcrypt/vcrypt/config"
 	"github.com/vcrypt/vcrypt/graph"
@@ -30,7 +31,8 @@ func NewPlan(g *Graph, comment string) (*Plan, error) {
 	}, nil
 }
 
-// BuildPlan constructs a Plan from the config data in r.
+// BuildPlan constructs a Plan from the config data in r. The Plan is sealed
+// by each seal in the config.
 func BuildPlan(r io.Reader) (*Plan, error) {
 	cp := config.Plan{}
 	if err := config.NewDecoder(r).Decode(&cp); err != nil {
@@ -42,7 +44,29 @@ func BuildPlan(r io.Reader) (*Plan, error) {
 		return nil, err
 	}
 
-	return NewPlan(g, cp.Comment)
+	plan, err := NewPlan(g, cp.Comment)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(cp.Seals))
+	for name := range cp.Seals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		slr, err := cp.Seals[name].Sealer()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := plan.AddSeal(slr); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
 }
 
 // Comment string
@@ -89,6 +113,45 @@ func (p *Plan) AddSeal(slr Sealer) (seal.Seal, error) {
 	return s, nil
 }
 
+// CheckSeals verifies the Plan seals.
+func (p *Plan) CheckSeals() error {
+	if len(p.seals) == 0 {
+		return nil
+	}
+
+	data, err := p.sealData()
+	if err != nil {
+		return err
+	}
+
+	seals, err := p.Seals()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range seals {
+		if err := s.Check(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckSealPolicy checks the seals & returns a seal.PolicyError unless they
+// satisfy the policy.
+func (p *Plan) CheckSealPolicy(pol *seal.Policy) error {
+	if err := p.CheckSeals(); err != nil {
+		return err
+	}
+
+	seals, err := p.Seals()
+	if err != nil {
+		return err
+	}
+
+	return pol.Check(seals)
+}
+
 // Digest is a unique series of bytes that identify the Plan.
 func (p *Plan) Digest() ([]byte, error) {
 	// HMAC(Nonce,Nodes[0].Digest|Comment|Seal[*].Digest)
@@ -1,10 +1,18 @@
 package vcrypt
 
 import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
 
 	"github.com/vcrypt/vcrypt/internal/test"
+	"github.com/vcrypt/vcrypt/seal"
 )
 
 var (
@@ -94,6 +102,107 @@ func TestPlan(t *testing.T) {
 	}
 }
 
+func TestPlanCheckSeals(t *testing.T) {
+	plan := buildPlan(twoManGraph, "two-man rule plan")
+	if _, err := plan.AddSeal(test.Sealer); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := plan.CheckSeals(); err != nil {
+		t.Fatal(err)
+	}
+
+	vault, err := NewVault(plan, "sealed plan vault")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan.comment = "tampered plan comment"
+	if err := plan.CheckSeals(); err == nil {
+		t.Errorf("want seal check error for tampered plan, got nil")
+	}
+	if err := vault.Lock(bytes.NewBufferString("secret"), twoManDriver); err == nil {
+		t.Errorf("want lock error for tampered plan, got nil")
+	}
+}
+
+func TestBuildPlanSeals(t *testing.T) {
+	f, err := ioutil.TempFile("", "vcrypt-seal-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	seed := bytes.Repeat([]byte{0x42}, ed25519.SeedSize)
+	if _, err := f.Write([]byte(base64.StdEncoding.EncodeToString(seed))); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	config := string(test.TwoManPlanConfig) + `
+[seal "expert"]
+ed25519-key = "` + f.Name() + `"
+`
+
+	plan, err := BuildPlan(bytes.NewBufferString(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seals, err := plan.Seals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seals) != 1 {
+		t.Fatalf("want 1 seal, got %d", len(seals))
+	}
+
+	want := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	if got := seals[0].(*seal.Ed25519).PublicKey; !bytes.Equal(want, got) {
+		t.Errorf("want seal public key %x, got %x", want, got)
+	}
+
+	if err := plan.CheckSeals(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBuildPlanBoxKey(t *testing.T) {
+	pubs := make([]string, 2)
+	for i := range pubs {
+		key, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubs[i] = base64.StdEncoding.EncodeToString(key.PublicKey().Bytes())
+	}
+
+	config := func(boxKey, secretKey string) string {
+		return `
+root = alice box
+
+[box "alice box"]
+publickey = ` + boxKey + `
+edge = alice material
+edge = alice key
+
+[material "alice material"]
+
+[x25519-key "alice key"]
+publickey = ` + secretKey + `
+`
+	}
+
+	if _, err := BuildPlan(bytes.NewBufferString(config(pubs[0], pubs[0]))); err != nil {
+		t.Error(err)
+	}
+	if _, err := BuildPlan(bytes.NewBufferString(config(pubs[0], pubs[1]))); err == nil {
+		t.Errorf("want build error for mismatched box key, got nil")
+	}
+}
+
 func buildPlan(g *Graph, desc string) *Plan {
 	plan, err := NewPlan(g, desc)
 	if err != nil {
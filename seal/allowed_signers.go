@@ -0,0 +1,203 @@
+package seal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// AllowedSigner is an entry of an OpenSSH allowed_signers file, as used by
+// `ssh-keygen -Y verify`.
+type AllowedSigner struct {
+	Principals []string
+	Namespaces []string
+	PublicKey  ssh.PublicKey
+
+	ValidAfter, ValidBefore time.Time
+}
+
+// ParseAllowedSigners parses the entries of an allowed_signers file.
+// Certificate authority entries are not supported and are skipped.
+func ParseAllowedSigners(data []byte) ([]AllowedSigner, error) {
+	var signers []AllowedSigner
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		signer, ok, err := parseAllowedSigner(line)
+		if err != nil {
+			return nil, fmt.Errorf("allowed signers line %d: %v", lineno, err)
+		}
+		if ok {
+			signers = append(signers, signer)
+		}
+	}
+
+	return signers, scanner.Err()
+}
+
+// Allows reports whether the signer may make signatures in the vcrypt
+// namespace with the public key at time t.
+func (a AllowedSigner) Allows(pub ssh.PublicKey, t time.Time) bool {
+	if !bytes.Equal(a.PublicKey.Marshal(), pub.Marshal()) {
+		return false
+	}
+
+	if !a.ValidAfter.IsZero() && t.Before(a.ValidAfter) {
+		return false
+	}
+	if !a.ValidBefore.IsZero() && t.After(a.ValidBefore) {
+		return false
+	}
+
+	if len(a.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range a.Namespaces {
+		if ns == SSHSigNamespace || ns == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func parseAllowedSigner(line string) (signer AllowedSigner, ok bool, err error) {
+	fields := splitFields(line)
+	if len(fields) < 3 {
+		return signer, false, fmt.Errorf("invalid entry %q", line)
+	}
+
+	signer.Principals = strings.Split(unquote(fields[0]), ",")
+	fields = fields[1:]
+
+	if strings.Contains(fields[0], "=") || strings.EqualFold(fields[0], "cert-authority") {
+		for _, opt := range splitOptions(fields[0]) {
+			name, value := opt, ""
+			if i := strings.Index(opt, "="); i >= 0 {
+				name, value = opt[:i], unquote(opt[i+1:])
+			}
+
+			switch strings.ToLower(name) {
+			case "cert-authority":
+				return signer, false, nil
+			case "namespaces":
+				signer.Namespaces = strings.Split(value, ",")
+			case "valid-after":
+				if signer.ValidAfter, err = parseSignerTime(value); err != nil {
+					return signer, false, err
+				}
+			case "valid-before":
+				if signer.ValidBefore, err = parseSignerTime(value); err != nil {
+					return signer, false, err
+				}
+			default:
+				return signer, false, fmt.Errorf("unsupported option %q", name)
+			}
+		}
+		fields = fields[1:]
+	}
+
+	if len(fields) < 2 {
+		return signer, false, fmt.Errorf("invalid entry %q", line)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return signer, false, err
+	}
+	if signer.PublicKey, err = ssh.ParsePublicKey(data); err != nil {
+		return signer, false, err
+	}
+	if signer.PublicKey.Type() != fields[0] {
+		return signer, false, fmt.Errorf("key type %q does not match key data", fields[0])
+	}
+
+	return signer, true, nil
+}
+
+// splitFields splits a line by whitespace outside of double quotes.
+func splitFields(line string) []string {
+	var (
+		fields []string
+		field  []rune
+		quoted bool
+	)
+
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			field = append(field, r)
+		case !quoted && (r == ' ' || r == '\t'):
+			if len(field) > 0 {
+				fields = append(fields, string(field))
+				field = field[:0]
+			}
+		default:
+			field = append(field, r)
+		}
+	}
+	if len(field) > 0 {
+		fields = append(fields, string(field))
+	}
+	return fields
+}
+
+// splitOptions splits an option list by commas outside of double quotes.
+func splitOptions(opts string) []string {
+	var (
+		list   []string
+		quoted bool
+		start  int
+	)
+
+	for i, r := range opts {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			list = append(list, opts[start:i])
+			start = i + 1
+		}
+	}
+	return append(list, opts[start:])
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// parseSignerTime parses a YYYYMMDD[HHMM[SS]][Z] timestamp. Timestamps are
+// in local time unless suffixed with Z.
+func parseSignerTime(value string) (time.Time, error) {
+	loc := time.Local
+	if strings.HasSuffix(value, "Z") {
+		value, loc = strings.TrimSuffix(value, "Z"), time.UTC
+	}
+
+	var layout string
+	switch len(value) {
+	case 8:
+		layout = "20060102"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+	}
+
+	return time.ParseInLocation(layout, value, loc)
+}
@@ -0,0 +1,67 @@
+package seal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestParseAllowedSigners(t *testing.T) {
+	data := []byte(`
+# engineering signers
+alice@example.com,alice@example.org ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDV2YnBai/vNXYgsFZQiR+oae09KR3blbTtxdzsZlxjy alice
+bob@example.com namespaces="git,vcrypt",valid-after="20200101Z" ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBALyqWLOkS3DRzAxEdBLDsdU0e5KNuToV58sMDvILj1yJ/QaSsfC+yBRYlmeNDJPM8yMKQ765BX9RuuikCaPyss=
+claire@example.com namespaces="git" ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDV2YnBai/vNXYgsFZQiR+oae09KR3blbTtxdzsZlxjy
+*.example.com cert-authority ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDV2YnBai/vNXYgsFZQiR+oae09KR3blbTtxdzsZlxjy
+david@example.com valid-before=20000101Z ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBALyqWLOkS3DRzAxEdBLDsdU0e5KNuToV58sMDvILj1yJ/QaSsfC+yBRYlmeNDJPM8yMKQ765BX9RuuikCaPyss=
+`)
+
+	signers, err := ParseAllowedSigners(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signers) != 4 {
+		t.Fatalf("want 4 allowed signers, got %d", len(signers))
+	}
+
+	edPub, _, _, _, err := ssh.ParseAuthorizedKey([]byte("ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDV2YnBai/vNXYgsFZQiR+oae09KR3blbTtxdzsZlxjy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecPub, _, _, _, err := ssh.ParseAuthorizedKey([]byte("ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBALyqWLOkS3DRzAxEdBLDsdU0e5KNuToV58sMDvILj1yJ/QaSsfC+yBRYlmeNDJPM8yMKQ765BX9RuuikCaPyss="))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := []string{"alice@example.com", "alice@example.org"}, signers[0].Principals; !reflect.DeepEqual(want, got) {
+		t.Errorf("want principals %q, got %q", want, got)
+	}
+	if want, got := []string{"git", "vcrypt"}, signers[1].Namespaces; !reflect.DeepEqual(want, got) {
+		t.Errorf("want namespaces %q, got %q", want, got)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		signer AllowedSigner
+		pub    ssh.PublicKey
+		allows bool
+	}{
+		{signers[0], edPub, true},
+		{signers[0], ecPub, false},
+		{signers[1], ecPub, true},
+		{signers[2], edPub, false},
+		{signers[3], ecPub, false},
+	}
+
+	for _, test := range tests {
+		if got := test.signer.Allows(test.pub, now); got != test.allows {
+			t.Errorf("want %v for %q allows %s, got %v", test.allows, test.signer.Principals, test.pub.Type(), got)
+		}
+	}
+
+	if _, err := ParseAllowedSigners([]byte("alice@example.com unknown-option=1 ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDV2YnBai/vNXYgsFZQiR+oae09KR3blbTtxdzsZlxjy")); err == nil {
+		t.Errorf("want unsupported option error, got nil")
+	}
+}
@@ -0,0 +1,125 @@
+package seal
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// NewEd25519 constructs an Ed25519 seal from a private key and signing data.
+func NewEd25519(key ed25519.PrivateKey, data []byte) (*Ed25519, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid Ed25519 private key")
+	}
+
+	nonce := make([]byte, 24)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return &Ed25519{
+		Nonce:     nonce,
+		PublicKey: []byte(key.Public().(ed25519.PublicKey)),
+		Signature: ed25519.Sign(key, data),
+	}, nil
+}
+
+// Check verifies the Ed25519 signature for the data using the public key.
+func (s *Ed25519) Check(data []byte) error {
+	if len(s.PublicKey) != ed25519.PublicKeySize {
+		return errors.New("invalid Ed25519 public key")
+	}
+	if !ed25519.Verify(ed25519.PublicKey(s.PublicKey), data, s.Signature) {
+		return errors.New("invalid Ed25519 signature")
+	}
+	return nil
+}
+
+// Digest returns an HMAC of the public key and signature data.
+func (s *Ed25519) Digest() ([]byte, error) {
+	// HMAC(Nonce,PublicKey|Signature)
+	hash := hmac.New(sha256.New, s.Nonce)
+
+	if _, err := hash.Write(s.PublicKey); err != nil {
+		return nil, err
+	}
+
+	if _, err := hash.Write(s.Signature); err != nil {
+		return nil, err
+	}
+
+	return hash.Sum(nil), nil
+}
+
+// Ed25519Sealer constructs Ed25519 seals with a private key.
+type Ed25519Sealer struct {
+	key ed25519.PrivateKey
+}
+
+// NewEd25519Sealer constructs a new Ed25519Sealer for the private key.
+func NewEd25519Sealer(key ed25519.PrivateKey) *Ed25519Sealer {
+	return &Ed25519Sealer{
+		key: key,
+	}
+}
+
+// Seal constructs an Ed25519 seal for the data.
+func (s *Ed25519Sealer) Seal(data []byte) (Seal, error) {
+	return NewEd25519(s.key, data)
+}
+
+// ParseEd25519Key parses an unencrypted Ed25519 private key in the OpenSSH
+// format, or a raw 32 byte seed or 64 byte private key. Raw keys may be base64
+// encoded.
+func ParseEd25519Key(data []byte) (ed25519.PrivateKey, error) {
+	if key, err := ssh.ParseRawPrivateKey(data); err == nil {
+		return ed25519Key(key)
+	} else if _, ok := err.(*ssh.PassphraseMissingError); ok {
+		return nil, err
+	}
+
+	if raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data))); err == nil {
+		data = raw
+	}
+
+	switch len(data) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(data), nil
+	case ed25519.PrivateKeySize:
+		key := ed25519.PrivateKey(append([]byte{}, data...))
+		if !ed25519.NewKeyFromSeed(key.Seed()).Equal(key) {
+			return nil, errors.New("invalid Ed25519 private key")
+		}
+		return key, nil
+	default:
+		return nil, errors.New("unsupported Ed25519 private key format")
+	}
+}
+
+// ParseEd25519KeyWithPassphrase parses a passphrase protected Ed25519 private
+// key in the OpenSSH format.
+func ParseEd25519KeyWithPassphrase(data, passphrase []byte) (ed25519.PrivateKey, error) {
+	key, err := ssh.ParseRawPrivateKeyWithPassphrase(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519Key(key)
+}
+
+func ed25519Key(key interface{}) (ed25519.PrivateKey, error) {
+	switch key := key.(type) {
+	case ed25519.PrivateKey:
+		return key, nil
+	case *ed25519.PrivateKey:
+		return *key, nil
+	default:
+		return nil, errors.New("ssh private key is not an Ed25519 key")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
+	"errors"
 	"io"
 
 	"golang.org/x/crypto/openpgp"
@@ -48,6 +49,59 @@ func (s *OpenPGP) Check(data []byte) error {
 	return err
 }
 
+// KeyIDs returns the primary key ID of the entity and the ID of the key that
+// made the signature.
+func (s *OpenPGP) KeyIDs() ([]uint64, error) {
+	r := packet.NewReader(bytes.NewBuffer(s.Entity))
+	e, err := openpgp.ReadEntity(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := packet.Read(bytes.NewBuffer(s.Signature))
+	if err != nil {
+		return nil, err
+	}
+
+	switch sig := p.(type) {
+	case *packet.Signature:
+		if sig.IssuerKeyId == nil {
+			return nil, errors.New("signature missing issuer key ID")
+		}
+		return []uint64{e.PrimaryKey.KeyId, *sig.IssuerKeyId}, nil
+	case *packet.SignatureV3:
+		return []uint64{e.PrimaryKey.KeyId, sig.IssuerKeyId}, nil
+	default:
+		return nil, errors.New("invalid signature data")
+	}
+}
+
+// Fingerprints returns the fingerprint of the entity primary key and of the key
+// that made the signature.
+func (s *OpenPGP) Fingerprints() ([][]byte, error) {
+	r := packet.NewReader(bytes.NewBuffer(s.Entity))
+	e, err := openpgp.ReadEntity(r)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := s.KeyIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	fps := [][]byte{e.PrimaryKey.Fingerprint[:]}
+	if ids[1] == e.PrimaryKey.KeyId {
+		return append(fps, e.PrimaryKey.Fingerprint[:]), nil
+	}
+	for _, sub := range e.Subkeys {
+		if sub.PublicKey.KeyId == ids[1] {
+			return append(fps, sub.PublicKey.Fingerprint[:]), nil
+		}
+	}
+	return nil, errors.New("signing key not found in entity")
+}
+
 // Digest returns an HMAC of the entity and signature data.
 func (s *OpenPGP) Digest() ([]byte, error) {
 	// HMAC(Nonce,Entity|Signature)
@@ -24,6 +24,22 @@ func TestSealOpenPGP(t *testing.T) {
 	if err := seal.Check(data); err != nil {
 		t.Error(err)
 	}
+
+	ids, err := seal.KeyIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids[0] != el[0].PrimaryKey.KeyId {
+		t.Errorf("want primary key ID %X, got %X", el[0].PrimaryKey.KeyId, ids[0])
+	}
+
+	fps, err := seal.Fingerprints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := el[0].PrimaryKey.Fingerprint[:], fps[0]; !bytes.Equal(want, got) {
+		t.Errorf("want primary key fingerprint %X, got %X", want, got)
+	}
 }
 
 func TestRoundTripOpenPGP(t *testing.T) {
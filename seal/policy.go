@@ -0,0 +1,145 @@
+package seal
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Signer is a trusted seal signer. A signer is identified by hex OpenPGP
+// fingerprints and SSH SHA256 fingerprints. Key IDs are not accepted: short
+// IDs are easily forged.
+type Signer struct {
+	Name string
+	IDs  []string
+}
+
+// Policy requires seals from a threshold of trusted signers.
+type Policy struct {
+	Threshold int
+	Signers   []Signer
+}
+
+// PolicyError reports the signers missing from an unsatisfied Policy.
+type PolicyError struct {
+	Threshold int
+	Signed    []string
+	Missing   []string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("seal policy requires %d of %d signers, have %d: missing %s",
+		e.Threshold, len(e.Signed)+len(e.Missing), len(e.Signed), strings.Join(e.Missing, ", "))
+}
+
+// Check returns a PolicyError unless the seals were made by at least a
+// threshold of the signers. The seals must already be checked against their
+// seal data.
+func (p *Policy) Check(seals []Seal) error {
+	if p.Threshold < 1 || p.Threshold > len(p.Signers) {
+		return errors.New("invalid seal policy threshold")
+	}
+
+	ids := make([]string, 0, len(seals))
+	for _, s := range seals {
+		sids, err := Identities(s)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, sids...)
+	}
+
+	perr := &PolicyError{Threshold: p.Threshold}
+	for _, signer := range p.Signers {
+		if signer.match(ids) {
+			perr.Signed = append(perr.Signed, signer.Name)
+		} else {
+			perr.Missing = append(perr.Missing, signer.Name)
+		}
+	}
+
+	if len(perr.Signed) < p.Threshold {
+		return perr
+	}
+	return nil
+}
+
+func (s Signer) match(ids []string) bool {
+	for _, id := range ids {
+		for _, sid := range s.IDs {
+			if matchIdentity(id, sid) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Identities returns the identities of the key that made the seal: hex
+// fingerprints for OpenPGP seals, and SHA256 fingerprints for Ed25519 & SSHSig
+// seals.
+func Identities(s Seal) ([]string, error) {
+	switch s := s.(type) {
+	case *OpenPGP:
+		fps, err := s.Fingerprints()
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]string, 0, len(fps))
+		for i, fp := range fps {
+			if i > 0 && string(fp) == string(fps[0]) {
+				continue
+			}
+			ids = append(ids, fmt.Sprintf("%X", fp))
+		}
+		return ids, nil
+	case *Ed25519:
+		pub, err := ssh.NewPublicKey(ed25519.PublicKey(s.PublicKey))
+		if err != nil {
+			return nil, err
+		}
+		return []string{ssh.FingerprintSHA256(pub)}, nil
+	case *SSHSig:
+		pub, err := s.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		return []string{ssh.FingerprintSHA256(pub)}, nil
+	default:
+		return nil, fmt.Errorf("unknown seal %#v", s)
+	}
+}
+
+// ParseIdentity returns the normalized form of a signer identity: a 40 hex
+// digit OpenPGP fingerprint, optionally grouped by spaces, or an SSH SHA256
+// fingerprint. OpenPGP key IDs are rejected.
+func ParseIdentity(id string) (string, error) {
+	if strings.HasPrefix(id, "SHA256:") {
+		hash, err := base64.RawStdEncoding.DecodeString(id[len("SHA256:"):])
+		if err != nil || len(hash) != 32 {
+			return "", fmt.Errorf("invalid SSH fingerprint %q", id)
+		}
+		return id, nil
+	}
+
+	fp := strings.ToUpper(strings.Replace(id, " ", "", -1))
+	data, err := hex.DecodeString(fp)
+	switch {
+	case err == nil && (len(data) == 4 || len(data) == 8):
+		return "", fmt.Errorf("%q is an OpenPGP key ID, not a full fingerprint", id)
+	case err != nil || len(data) != 20:
+		return "", fmt.Errorf("%q is not a full OpenPGP fingerprint", id)
+	}
+	return fp, nil
+}
+
+func matchIdentity(id, signerID string) bool {
+	signerID, err := ParseIdentity(signerID)
+	return err == nil && id == signerID
+}
@@ -0,0 +1,103 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestPolicy(t *testing.T) {
+	data := []byte("a message to seal")
+
+	var (
+		seals []Seal
+		fps   []string
+	)
+	for _, b := range []byte{0x01, 0x02, 0x03} {
+		key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{b}, ed25519.SeedSize))
+
+		s, err := NewEd25519(key, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seals = append(seals, s)
+
+		pub, err := ssh.NewPublicKey(key.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+		fps = append(fps, ssh.FingerprintSHA256(pub))
+	}
+
+	pol := &Policy{
+		Threshold: 2,
+		Signers: []Signer{
+			{Name: "alice", IDs: []string{fps[0]}},
+			{Name: "bob", IDs: []string{"37D4A8F1A26C3C4E8F6E0B3FAE9D3A7AFAEDDE7A", fps[1]}},
+			{Name: "carol", IDs: []string{fps[2]}},
+		},
+	}
+
+	tests := []struct {
+		seals   []Seal
+		missing []string
+	}{
+		{seals: seals},
+		{seals: seals[1:]},
+		{seals: seals[:1], missing: []string{"bob", "carol"}},
+		{seals: seals[2:], missing: []string{"alice", "bob"}},
+		{seals: nil, missing: []string{"alice", "bob", "carol"}},
+	}
+
+	for _, test := range tests {
+		err := pol.Check(test.seals)
+		if test.missing == nil {
+			if err != nil {
+				t.Error(err)
+			}
+			continue
+		}
+
+		perr, ok := err.(*PolicyError)
+		if !ok {
+			t.Errorf("want PolicyError, got %v", err)
+			continue
+		}
+		if !reflect.DeepEqual(test.missing, perr.Missing) {
+			t.Errorf("want missing signers %v, got %v", test.missing, perr.Missing)
+		}
+	}
+
+	pol.Threshold = 4
+	if err := pol.Check(seals); err == nil {
+		t.Errorf("want threshold error for 4 of 3 signers, got nil")
+	}
+}
+
+func TestMatchIdentity(t *testing.T) {
+	const fp = "37D4A8F1A26C3C4E8F6E0B3FAE9D3A7AFAEDDE7A"
+
+	tests := []struct {
+		id, signerID string
+		match        bool
+	}{
+		{fp, fp, true},
+		{fp, "37d4a8f1a26c3c4e8f6e0b3fae9d3a7afaedde7a", true},
+		{fp, "37D4 A8F1 A26C 3C4E 8F6E  0B3F AE9D 3A7A FAED DE7A", true},
+		{fp, "AE9D3A7AFAEDDE7A", false},
+		{fp, "FAEDDE7A", false},
+		{fp, "07D4A8F1A26C3C4E8F6E0B3FAE9D3A7AFAEDDE7A", false},
+		{"SHA256:Wb3tN6pb64q/c8jyBzbpa84nJweyEvws1XPrdt+MBmk", "SHA256:Wb3tN6pb64q/c8jyBzbpa84nJweyEvws1XPrdt+MBmk", true},
+		{"SHA256:Wb3tN6pb64q/c8jyBzbpa84nJweyEvws1XPrdt+MBmk", "SHA256:wb3tN6pb64q/c8jyBzbpa84nJweyEvws1XPrdt+MBmk", false},
+		{"SHA256:Wb3t", "SHA256:Wb3t", false},
+	}
+
+	for _, test := range tests {
+		if got := matchIdentity(test.id, test.signerID); got != test.match {
+			t.Errorf("want matchIdentity(%q, %q) = %t, got %t", test.id, test.signerID, test.match, got)
+		}
+	}
+}
@@ -8,6 +8,8 @@
 	It is generated from these files:
 		seal/seal.proto
 		seal/openpgp.proto
+		seal/ed25519.proto
+		seal/sshsig.proto
 
 	It has these top-level messages:
 		Envelope
@@ -26,6 +28,8 @@ var _ = proto.Marshal
 
 type Envelope struct {
 	Openpgp *OpenPGP `protobuf:"bytes,1,opt,name=openpgp" json:"openpgp,omitempty"`
+	Ed25519 *Ed25519 `protobuf:"bytes,2,opt,name=ed25519" json:"ed25519,omitempty"`
+	SSHSig  *SSHSig  `protobuf:"bytes,3,opt,name=sshsig" json:"sshsig,omitempty"`
 }
 
 func (m *Envelope) Reset()         { *m = Envelope{} }
@@ -39,6 +43,20 @@ func (m *Envelope) GetOpenpgp() *OpenPGP {
 	return nil
 }
 
+func (m *Envelope) GetEd25519() *Ed25519 {
+	if m != nil {
+		return m.Ed25519
+	}
+	return nil
+}
+
+func (m *Envelope) GetSSHSig() *SSHSig {
+	if m != nil {
+		return m.SSHSig
+	}
+	return nil
+}
+
 func (m *Envelope) Marshal() (data []byte, err error) {
 	size := m.Size()
 	data = make([]byte, size)
@@ -64,6 +82,26 @@ func (m *Envelope) MarshalTo(data []byte) (int, error) {
 		}
 		i += n1
 	}
+	if m.Ed25519 != nil {
+		data[i] = 0x12
+		i++
+		i = encodeVarintSeal(data, i, uint64(m.Ed25519.Size()))
+		n2, err := m.Ed25519.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.SSHSig != nil {
+		data[i] = 0x1a
+		i++
+		i = encodeVarintSeal(data, i, uint64(m.SSHSig.Size()))
+		n3, err := m.SSHSig.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
 	return i, nil
 }
 
@@ -101,6 +139,14 @@ func (m *Envelope) Size() (n int) {
 		l = m.Openpgp.Size()
 		n += 1 + l + sovSeal(uint64(l))
 	}
+	if m.Ed25519 != nil {
+		l = m.Ed25519.Size()
+		n += 1 + l + sovSeal(uint64(l))
+	}
+	if m.SSHSig != nil {
+		l = m.SSHSig.Size()
+		n += 1 + l + sovSeal(uint64(l))
+	}
 	return n
 }
 
@@ -121,6 +167,12 @@ func (this *Envelope) GetValue() interface{} {
 	if this.Openpgp != nil {
 		return this.Openpgp
 	}
+	if this.Ed25519 != nil {
+		return this.Ed25519
+	}
+	if this.SSHSig != nil {
+		return this.SSHSig
+	}
 	return nil
 }
 
@@ -128,6 +180,10 @@ func (this *Envelope) SetValue(value interface{}) bool {
 	switch vt := value.(type) {
 	case *OpenPGP:
 		this.Openpgp = vt
+	case *Ed25519:
+		this.Ed25519 = vt
+	case *SSHSig:
+		this.SSHSig = vt
 	default:
 		return false
 	}
@@ -182,6 +238,66 @@ func (m *Envelope) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ed25519", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthSeal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Ed25519 == nil {
+				m.Ed25519 = &Ed25519{}
+			}
+			if err := m.Ed25519.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SSHSig", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthSeal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SSHSig == nil {
+				m.SSHSig = &SSHSig{}
+			}
+			if err := m.SSHSig.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			var sizeOfWire int
 			for {
@@ -0,0 +1,193 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// SSHSigNamespace is the namespace of SSHSig signatures, as passed to
+	// `ssh-keygen -Y sign -n`.
+	SSHSigNamespace = "vcrypt"
+
+	sshSigMagic   = "SSHSIG"
+	sshSigVersion = 1
+)
+
+// sshSigBlob is the SSHSIG signature format following the magic preamble.
+type sshSigBlob struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// sshSigSignedData is the data signed by the key following the magic preamble.
+type sshSigSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// NewSSHSig constructs an SSHSig seal from an SSH signer and signing data. The
+// signature blob is compatible with `ssh-keygen -Y verify -n vcrypt`.
+func NewSSHSig(signer ssh.Signer, data []byte) (*SSHSig, error) {
+	nonce := make([]byte, 24)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	sum := sha512.Sum512(data)
+	signed := sshSigSignedData{
+		Namespace:     SSHSigNamespace,
+		HashAlgorithm: "sha512",
+		Hash:          sum[:],
+	}
+	msg := append([]byte(sshSigMagic), ssh.Marshal(signed)...)
+
+	var (
+		sig *ssh.Signature
+		err error
+	)
+
+	pub := signer.PublicKey()
+	if pub.Type() == ssh.KeyAlgoRSA {
+		asigner, ok := signer.(ssh.AlgorithmSigner)
+		if !ok {
+			return nil, errors.New("ssh signer does not support rsa-sha2-512 signatures")
+		}
+		sig, err = asigner.SignWithAlgorithm(rand.Reader, msg, ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = signer.Sign(rand.Reader, msg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	blob := sshSigBlob{
+		Version:       sshSigVersion,
+		PublicKey:     pub.Marshal(),
+		Namespace:     SSHSigNamespace,
+		HashAlgorithm: signed.HashAlgorithm,
+		Signature:     ssh.Marshal(sig),
+	}
+
+	return &SSHSig{
+		Nonce:     nonce,
+		Signature: append([]byte(sshSigMagic), ssh.Marshal(blob)...),
+	}, nil
+}
+
+// Check verifies the SSHSIG signature for the data using the embedded public
+// key.
+func (s *SSHSig) Check(data []byte) error {
+	blob, err := s.blob()
+	if err != nil {
+		return err
+	}
+
+	pub, err := ssh.ParsePublicKey(blob.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	var hash hash.Hash
+	switch blob.HashAlgorithm {
+	case "sha256":
+		hash = sha256.New()
+	case "sha512":
+		hash = sha512.New()
+	default:
+		return fmt.Errorf("unsupported SSHSIG hash algorithm %q", blob.HashAlgorithm)
+	}
+	hash.Write(data)
+
+	sig := &ssh.Signature{}
+	if err := ssh.Unmarshal(blob.Signature, sig); err != nil {
+		return err
+	}
+	if sig.Format == ssh.KeyAlgoRSA {
+		return errors.New("unsupported SSHSIG ssh-rsa signature")
+	}
+
+	signed := sshSigSignedData{
+		Namespace:     blob.Namespace,
+		Reserved:      blob.Reserved,
+		HashAlgorithm: blob.HashAlgorithm,
+		Hash:          hash.Sum(nil),
+	}
+	msg := append([]byte(sshSigMagic), ssh.Marshal(signed)...)
+
+	return pub.Verify(msg, sig)
+}
+
+// Digest returns an HMAC of the signature data.
+func (s *SSHSig) Digest() ([]byte, error) {
+	// HMAC(Nonce,Signature)
+	hash := hmac.New(sha256.New, s.Nonce)
+
+	if _, err := hash.Write(s.Signature); err != nil {
+		return nil, err
+	}
+
+	return hash.Sum(nil), nil
+}
+
+// PublicKey returns the public key embedded in the signature.
+func (s *SSHSig) PublicKey() (ssh.PublicKey, error) {
+	blob, err := s.blob()
+	if err != nil {
+		return nil, err
+	}
+
+	return ssh.ParsePublicKey(blob.PublicKey)
+}
+
+func (s *SSHSig) blob() (*sshSigBlob, error) {
+	if !bytes.HasPrefix(s.Signature, []byte(sshSigMagic)) {
+		return nil, errors.New("invalid SSHSIG signature")
+	}
+
+	blob := &sshSigBlob{}
+	if err := ssh.Unmarshal(s.Signature[len(sshSigMagic):], blob); err != nil {
+		return nil, err
+	}
+
+	if blob.Version != sshSigVersion {
+		return nil, fmt.Errorf("unsupported SSHSIG version %d", blob.Version)
+	}
+	if blob.Namespace != SSHSigNamespace {
+		return nil, fmt.Errorf("invalid SSHSIG namespace %q", blob.Namespace)
+	}
+
+	return blob, nil
+}
+
+// SSHSigSealer constructs SSHSig seals with an SSH signer.
+type SSHSigSealer struct {
+	signer ssh.Signer
+}
+
+// NewSSHSigSealer constructs a new SSHSigSealer for the signer.
+func NewSSHSigSealer(signer ssh.Signer) *SSHSigSealer {
+	return &SSHSigSealer{
+		signer: signer,
+	}
+}
+
+// Seal constructs an SSHSig seal for the data.
+func (s *SSHSigSealer) Seal(data []byte) (Seal, error) {
+	return NewSSHSig(s.signer, data)
+}
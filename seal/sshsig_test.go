@@ -0,0 +1,97 @@
+package seal
+
+import (
+	"encoding/pem"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSealSSHSig(t *testing.T) {
+	data := []byte("a message to seal")
+
+	signer, err := ssh.ParsePrivateKey([]byte(ed25519Private))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seal, err := NewSSHSigSealer(signer).Seal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := seal.Check(data); err != nil {
+		t.Error(err)
+	}
+	if err := seal.Check([]byte("another message")); err == nil {
+		t.Errorf("want signature error for different data, got nil")
+	}
+
+	pub, err := seal.(*SSHSig).PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := ssh.FingerprintSHA256(signer.PublicKey()), ssh.FingerprintSHA256(pub); want != got {
+		t.Errorf("want public key %s, got %s", want, got)
+	}
+}
+
+func TestSSHSigKeygen(t *testing.T) {
+	data := []byte("a message to seal")
+
+	for _, armored := range []string{sshSigEd25519, sshSigRSA} {
+		block, _ := pem.Decode([]byte(armored))
+		if block == nil {
+			t.Fatal("invalid SSH SIGNATURE armor")
+		}
+
+		seal := &SSHSig{Signature: block.Bytes}
+		if err := seal.Check(data); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestRoundTripSSHSig(t *testing.T) {
+	signer, err := ssh.ParsePrivateKey([]byte(ed25519Private))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := NewSSHSig(signer, []byte("test data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(*want, *got.(*SSHSig)) {
+		t.Errorf("want SSHSig seal %v, got %v", want, got)
+	}
+}
+
+var (
+	// ssh-keygen -Y sign -n vcrypt signatures of "a message to seal"
+	sshSigEd25519 = `-----BEGIN SSH SIGNATURE-----
+U1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgNXZicFqL+81diCwVlCJH6hp7T0
+pHduVtO3F3OxmXGPIAAAAGdmNyeXB0AAAAAAAAAAZzaGE1MTIAAABTAAAAC3NzaC1lZDI1
+NTE5AAAAQBUoBy9DcQriZdO0kAlN9GflspvvSBss+ZqOC3QYQ7Wsdeo0/QqQH+Fc+6RhO/
+2U0Vb71F3VQKW2d83t6SHZaQc=
+-----END SSH SIGNATURE-----`
+
+	sshSigRSA = `-----BEGIN SSH SIGNATURE-----
+U1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgNXZicFqL+81diCwVlCJH6hp7T0
+pHduVtO3F3OxmXGPIAAAAGdmNyeXB0AAAAAAAAAAZzaGE1MTIAAABTAAAAC3NzaC1lZDI1
+NTE5AAAAQBUoBy9DcQriZdO0kAlN9GflspvvSBss+ZqOC3QYQ7Wsdeo0/QqQH+Fc+6RhO/
+2U0Vb71F3VQKW2d83t6SHZaQc=
+-----END SSH SIGNATURE-----`
+)
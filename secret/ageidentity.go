@@ -0,0 +1,73 @@
+package secret
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// ErrNoAgeIdentity is returned by Load when no identity matches the secret.
+var ErrNoAgeIdentity = errors.New("no matching age identity")
+
+// NewAgeIdentity constructs a new AgeIdentity for the identities of the age
+// X25519 recipients. Any X25519 identity matches if recipients is empty.
+func NewAgeIdentity(recipients []string, comment string) (*AgeIdentity, error) {
+	for _, s := range recipients {
+		if _, err := age.ParseX25519Recipient(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return &AgeIdentity{
+		Recipients: recipients,
+		comment:    comment,
+	}, nil
+}
+
+// Comment string
+func (s *AgeIdentity) Comment() string {
+	return s.comment
+}
+
+// Phase is Unlock
+func (s *AgeIdentity) Phase() Phase { return Unlock }
+
+// Load parses an age identity file, as written by age-keygen, and returns the
+// matching X25519 identities as newline separated "AGE-SECRET-KEY-1..."
+// strings, as the age cryptex expects.
+func (s *AgeIdentity) Load(r io.Reader) ([][]byte, error) {
+	ids, err := age.ParseIdentities(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, id := range ids {
+		id, ok := id.(*age.X25519Identity)
+		if !ok || !s.Match(id.Recipient().String()) {
+			continue
+		}
+		keys = append(keys, id.String())
+	}
+	if len(keys) == 0 {
+		return nil, ErrNoAgeIdentity
+	}
+
+	return [][]byte{[]byte(strings.Join(keys, "\n"))}, nil
+}
+
+// Match reports whether the identity for the recipient matches the secret.
+func (s *AgeIdentity) Match(recipient string) bool {
+	if len(s.Recipients) == 0 {
+		return true
+	}
+
+	for _, rcpt := range s.Recipients {
+		if rcpt == recipient {
+			return true
+		}
+	}
+	return false
+}
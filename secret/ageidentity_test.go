@@ -0,0 +1,60 @@
+package secret
+
+import (
+	"bytes"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func TestAgeIdentity(t *testing.T) {
+	alice, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyFile := "# created: 2026-10-17T00:00:00Z\n" +
+		"# public key: " + alice.Recipient().String() + "\n" +
+		alice.String() + "\n" +
+		bob.String() + "\n"
+
+	sec, err := NewAgeIdentity([]string{bob.Recipient().String()}, "test AgeIdentity secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := sec.Load(bytes.NewBufferString(keyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := bob.String(), string(data[0]); want != got {
+		t.Errorf("want identity %q, got %q", want, got)
+	}
+
+	sec, err = NewAgeIdentity(nil, "any identity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err = sec.Load(bytes.NewBufferString(keyFile)); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := alice.String()+"\n"+bob.String(), string(data[0]); want != got {
+		t.Errorf("want identities %q, got %q", want, got)
+	}
+
+	sec, err = NewAgeIdentity([]string{bob.Recipient().String()}, "missing identity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sec.Load(bytes.NewBufferString(alice.String())); err != ErrNoAgeIdentity {
+		t.Errorf("want error %q, got %v", ErrNoAgeIdentity, err)
+	}
+
+	if _, err := NewAgeIdentity([]string{"age1invalid"}, ""); err == nil {
+		t.Errorf("want error for invalid recipient, got nil")
+	}
+}
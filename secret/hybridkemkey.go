@@ -0,0 +1,91 @@
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"github.com/vcrypt/vcrypt/cryptex"
+)
+
+const hybridKEMKeyPEMType = "VCRYPT HYBRID KEM PRIVATE KEY"
+
+// NewHybridKEMKey constructs a new HybridKEMKey for the combined ML-KEM-768 &
+// X25519 public key of a hybrid-kem cryptex.
+func NewHybridKEMKey(publicKey []byte, comment string) (*HybridKEMKey, error) {
+	if len(publicKey) != cryptex.HybridKEMPublicKeySize {
+		return nil, errors.New("invalid hybrid KEM public key size")
+	}
+
+	return &HybridKEMKey{
+		PublicKey: publicKey,
+		comment:   comment,
+	}, nil
+}
+
+// Comment string
+func (s *HybridKEMKey) Comment() string {
+	return s.comment
+}
+
+// Phase is Unlock
+func (s *HybridKEMKey) Phase() Phase { return Unlock }
+
+// Load parses & verifies an unencrypted private key file and returns the
+// private key in the form the hybrid-kem cryptex expects.
+func (s *HybridKEMKey) Load(r io.Reader) ([][]byte, error) {
+	return s.LoadWithPassphrase(r, nil)
+}
+
+// LoadWithPassphrase is Load for a passphrase protected private key file.
+func (s *HybridKEMKey) LoadWithPassphrase(r io.Reader, passphrase []byte) ([][]byte, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := parseKeyFile(hybridKEMKeyPEMType, data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := cryptex.HybridKEMPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Match(pub) {
+		return nil, errors.New("wrong private key for hybrid KEM public key")
+	}
+
+	return [][]byte{key}, nil
+}
+
+// Match reports whether pub is the public key of the secret.
+func (s *HybridKEMKey) Match(pub []byte) bool {
+	return bytes.Equal(s.PublicKey, pub)
+}
+
+// MarshalHybridKEMPrivateKey returns the PEM encoded key file for a hybrid
+// KEM private key, encrypted unless the passphrase is empty.
+func MarshalHybridKEMPrivateKey(key, passphrase []byte) ([]byte, error) {
+	pub, err := cryptex.HybridKEMPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalKeyFile(hybridKEMKeyPEMType, key, pub, passphrase)
+}
+
+// ParseHybridKEMPublicKey returns the public key of a PEM encoded key file
+// without decrypting the private key.
+func ParseHybridKEMPublicKey(data []byte) ([]byte, error) {
+	pub, err := parseKeyFilePublicKey(hybridKEMKeyPEMType, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(pub) != cryptex.HybridKEMPublicKeySize {
+		return nil, errors.New("invalid hybrid KEM key file public key")
+	}
+	return pub, nil
+}
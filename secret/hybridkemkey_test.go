@@ -0,0 +1,61 @@
+package secret
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/vcrypt/vcrypt/cryptex"
+)
+
+func TestHybridKEMKey(t *testing.T) {
+	priv, pub, err := cryptex.GenerateHybridKEMKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sec, err := NewHybridKEMKey(pub, "test HybridKEMKey secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := MarshalHybridKEMPrivateKey(priv, []byte("test passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fpub, err := ParseHybridKEMPublicKey(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sec.Match(fpub) {
+		t.Errorf("want key file public key to match secret")
+	}
+
+	if _, err := sec.Load(bytes.NewReader(data)); err != ErrKeyFileEncrypted {
+		t.Errorf("want error %q, got %v", ErrKeyFileEncrypted, err)
+	}
+
+	keys, err := sec.LoadWithPassphrase(bytes.NewReader(data), []byte("test passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := cryptex.NewHybridKEM(pub, "test hybrid-kem")
+	inputs := [][]byte{nil, nil}
+	if err := c.Close(inputs, [][]byte{[]byte("secret")}); err != nil {
+		t.Fatal(err)
+	}
+
+	secrets := [][]byte{nil}
+	if err := c.Open(secrets, [][]byte{inputs[0], keys[0]}); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := []byte("secret"), secrets[0]; !bytes.Equal(want, got) {
+		t.Errorf("want hybrid-kem secret %q, got %q", want, got)
+	}
+
+	if _, err := sec.Load(bytes.NewReader([]byte("-----BEGIN VCRYPT X25519 PRIVATE KEY-----\n-----END VCRYPT X25519 PRIVATE KEY-----\n"))); err == nil {
+		t.Errorf("want error for x25519 key file, got nil")
+	}
+}
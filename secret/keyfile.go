@@ -0,0 +1,128 @@
+package secret
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keyFileEncryption = "scrypt-secretbox"
+
+	// scrypt parameters for passphrase protected key files
+	keyFileScryptN = 1 << 15
+	keyFileScryptR = 8
+	keyFileScryptP = 1
+)
+
+// ErrKeyFileEncrypted is returned by Load for a passphrase protected key file.
+var ErrKeyFileEncrypted = errors.New("key file is passphrase protected")
+
+// marshalKeyFile returns a PEM encoded private key file. The key is encrypted
+// with an scrypt derived secretbox key unless the passphrase is empty. The
+// public key is stored in the clear so the key file can be found without the
+// passphrase.
+func marshalKeyFile(typ string, key, pub, passphrase []byte) ([]byte, error) {
+	block := &pem.Block{
+		Type: typ,
+		Headers: map[string]string{
+			"Public-Key": base64.StdEncoding.EncodeToString(pub),
+		},
+		Bytes: key,
+	}
+
+	if len(passphrase) > 0 {
+		salt, nonce := make([]byte, 16), [24]byte{}
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+			return nil, err
+		}
+
+		skey, err := keyFileKDF(passphrase, salt)
+		if err != nil {
+			return nil, err
+		}
+
+		block.Headers["Encryption"] = keyFileEncryption
+		block.Headers["Salt"] = base64.StdEncoding.EncodeToString(salt)
+		block.Bytes = secretbox.Seal(nonce[:], key, &nonce, skey)
+	}
+
+	return pem.EncodeToMemory(block), nil
+}
+
+func parseKeyFilePublicKey(typ string, data []byte) ([]byte, error) {
+	block, err := decodeKeyFile(typ, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(block.Headers["Public-Key"])
+}
+
+func parseKeyFile(typ string, data, passphrase []byte) ([]byte, error) {
+	block, err := decodeKeyFile(typ, data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Headers["Encryption"] {
+	case "":
+		return block.Bytes, nil
+	case keyFileEncryption:
+	default:
+		return nil, errors.New("unsupported key file encryption")
+	}
+
+	if len(passphrase) == 0 {
+		return nil, ErrKeyFileEncrypted
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(block.Headers["Salt"])
+	if err != nil {
+		return nil, err
+	}
+	if len(block.Bytes) < 24+secretbox.Overhead {
+		return nil, errors.New("invalid key file")
+	}
+
+	skey, err := keyFileKDF(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := [24]byte{}
+	copy(nonce[:], block.Bytes[:24])
+
+	key, ok := secretbox.Open(nil, block.Bytes[24:], &nonce, skey)
+	if !ok {
+		return nil, errors.New("key file decryption failure")
+	}
+	return key, nil
+}
+
+func decodeKeyFile(typ string, data []byte) (*pem.Block, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != typ {
+		return nil, errors.New("invalid key file")
+	}
+	return block, nil
+}
+
+func keyFileKDF(passphrase, salt []byte) (*[32]byte, error) {
+	dk, err := scrypt.Key(passphrase, salt, keyFileScryptN, keyFileScryptR, keyFileScryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	key := [32]byte{}
+	copy(key[:], dk)
+	return &key, nil
+}
@@ -0,0 +1,134 @@
+package secret
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// pkcs11Context separates keys unwrapped by the token from other uses of the
+// token key.
+const pkcs11Context = "vcrypt pkcs11 unwrap\x00"
+
+// PKCS11Token performs private key operations with keys that stay on a
+// PKCS#11 token.
+type PKCS11Token interface {
+	// DecryptOAEP decrypts the ciphertext with the labeled RSA private key
+	// using OAEP with SHA-256.
+	DecryptOAEP(label string, ciphertext []byte) ([]byte, error)
+
+	// ECDH returns the shared secret of the labeled EC private key & the
+	// uncompressed peer point.
+	ECDH(label string, peer []byte) ([]byte, error)
+}
+
+// NewPKCS11Key constructs a new PKCS11Key for the private key with the label
+// on a PKCS#11 token, & the PKIX encoded RSA or P-256 public key. The token is
+// found by its label, or by slot when token is empty. A random key is wrapped
+// with OAEP for an RSA key, or an ephemeral ECDH public key is generated for a
+// P-256 key.
+func NewPKCS11Key(token string, slot uint64, label string, publicKey []byte, comment string) (*PKCS11Key, error) {
+	if label == "" {
+		return nil, errors.New("pkcs11 key label is required")
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var ct []byte
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		key := make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, err
+		}
+		if ct, err = rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, key, nil); err != nil {
+			return nil, err
+		}
+	case *ecdsa.PublicKey:
+		if _, err := pub.ECDH(); err != nil || pub.Curve.Params().Name != "P-256" {
+			return nil, errors.New("pkcs11 EC keys must be P-256")
+		}
+
+		eph, err := ecdh.P256().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		ct = eph.PublicKey().Bytes()
+	default:
+		return nil, errors.New("pkcs11 key must be RSA or P-256")
+	}
+
+	nonce := make([]byte, 24)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return &PKCS11Key{
+		Token:      token,
+		Slot:       slot,
+		Label:      label,
+		PublicKey:  publicKey,
+		Ciphertext: ct,
+		Nonce:      nonce,
+		comment:    comment,
+	}, nil
+}
+
+// Comment string
+func (s *PKCS11Key) Comment() string {
+	return s.comment
+}
+
+// Phase is Dual: the key is unwrapped on the token when the vault is locked,
+// so the token & its PIN are needed to lock as well as to unlock.
+func (s *PKCS11Key) Phase() Phase { return Dual }
+
+// Unwrap performs the RSA-OAEP decryption or ECDH key agreement with the
+// private key on the token & returns the raw result for Load.
+func (s *PKCS11Key) Unwrap(tok PKCS11Token) ([]byte, error) {
+	pub, err := x509.ParsePKIXPublicKey(s.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		return tok.DecryptOAEP(s.Label, s.Ciphertext)
+	case *ecdsa.PublicKey:
+		return tok.ECDH(s.Label, s.Ciphertext)
+	default:
+		return nil, errors.New("pkcs11 key must be RSA or P-256")
+	}
+}
+
+// Load reads the data unwrapped by the token and returns the key derived from
+// it.
+func (s *PKCS11Key) Load(r io.Reader) ([][]byte, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty pkcs11 unwrapped key")
+	}
+
+	// HKDF(unwrapped, salt=Nonce, info=context|label)
+	kdf := hkdf.New(sha256.New, data, s.Nonce, []byte(pkcs11Context+s.Label))
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(kdf, key); err != nil {
+		return nil, err
+	}
+	return [][]byte{key}, nil
+}
@@ -0,0 +1,106 @@
+package secret
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"testing"
+)
+
+// softToken is a PKCS11Token with in memory keys.
+type softToken map[string]crypto.PrivateKey
+
+func (t softToken) DecryptOAEP(label string, ciphertext []byte) ([]byte, error) {
+	key, ok := t[label].(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("no RSA key for label")
+	}
+	return rsa.DecryptOAEP(sha256.New(), rand.Reader, key, ciphertext, nil)
+}
+
+func (t softToken) ECDH(label string, peer []byte) ([]byte, error) {
+	key, ok := t[label].(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("no EC key for label")
+	}
+
+	priv, err := key.ECDH()
+	if err != nil {
+		return nil, err
+	}
+	pub, err := ecdh.P256().NewPublicKey(peer)
+	if err != nil {
+		return nil, err
+	}
+	return priv.ECDH(pub)
+}
+
+func TestPKCS11Key(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tok := softToken{"rsa root": rsaKey, "ec root": ecKey}
+
+	for label, pub := range map[string]crypto.PublicKey{"rsa root": &rsaKey.PublicKey, "ec root": &ecKey.PublicKey} {
+		pkix, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sec, err := NewPKCS11Key("vault token", 0, label, pkix, "test PKCS11Key secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var keys [][]byte
+		for i := 0; i < 2; i++ {
+			data, err := sec.Unwrap(tok)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			key, err := sec.Load(bytes.NewBuffer(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			keys = append(keys, key[0])
+		}
+
+		if len(keys[0]) != 32 {
+			t.Errorf("want 32 byte key, got %d bytes", len(keys[0]))
+		}
+		if !bytes.Equal(keys[0], keys[1]) {
+			t.Errorf("want equal keys for %s, got %x & %x", label, keys[0], keys[1])
+		}
+
+		sec.Label = "missing"
+		if _, err := sec.Unwrap(tok); err == nil {
+			t.Errorf("want unwrap error for missing key, got nil")
+		}
+	}
+
+	ecKey384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkix, err := x509.MarshalPKIXPublicKey(&ecKey384.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPKCS11Key("vault token", 0, "ec root", pkix, ""); err == nil {
+		t.Errorf("want error for P-384 key, got nil")
+	}
+}
@@ -10,6 +10,11 @@
 		secret/password.proto
 		secret/openpgpkey.proto
 		secret/sshkey.proto
+		secret/sshagent.proto
+		secret/x25519key.proto
+		secret/hybridkemkey.proto
+		secret/ageidentity.proto
+		secret/pkcs11key.proto
 
 	It has these top-level messages:
 		Envelope
@@ -27,9 +32,14 @@ import fmt "fmt"
 var _ = proto.Marshal
 
 type Envelope struct {
-	Password   *Password   `protobuf:"bytes,1,opt,name=password" json:"password,omitempty"`
-	OpenPGPKey *OpenPGPKey `protobuf:"bytes,2,opt,name=openpgpkey" json:"openpgpkey,omitempty"`
-	SSHKey     *SSHKey     `protobuf:"bytes,3,opt,name=sshkey" json:"sshkey,omitempty"`
+	Password     *Password     `protobuf:"bytes,1,opt,name=password" json:"password,omitempty"`
+	OpenPGPKey   *OpenPGPKey   `protobuf:"bytes,2,opt,name=openpgpkey" json:"openpgpkey,omitempty"`
+	SSHKey       *SSHKey       `protobuf:"bytes,3,opt,name=sshkey" json:"sshkey,omitempty"`
+	SSHAgent     *SSHAgent     `protobuf:"bytes,4,opt,name=sshagent" json:"sshagent,omitempty"`
+	X25519Key    *X25519Key    `protobuf:"bytes,5,opt,name=x25519key" json:"x25519key,omitempty"`
+	HybridKEMKey *HybridKEMKey `protobuf:"bytes,6,opt,name=hybridkemkey" json:"hybridkemkey,omitempty"`
+	AgeIdentity  *AgeIdentity  `protobuf:"bytes,7,opt,name=ageidentity" json:"ageidentity,omitempty"`
+	PKCS11Key    *PKCS11Key    `protobuf:"bytes,8,opt,name=pkcs11key" json:"pkcs11key,omitempty"`
 }
 
 func (m *Envelope) Reset()         { *m = Envelope{} }
@@ -57,6 +67,41 @@ func (m *Envelope) GetSSHKey() *SSHKey {
 	return nil
 }
 
+func (m *Envelope) GetSSHAgent() *SSHAgent {
+	if m != nil {
+		return m.SSHAgent
+	}
+	return nil
+}
+
+func (m *Envelope) GetX25519Key() *X25519Key {
+	if m != nil {
+		return m.X25519Key
+	}
+	return nil
+}
+
+func (m *Envelope) GetHybridKEMKey() *HybridKEMKey {
+	if m != nil {
+		return m.HybridKEMKey
+	}
+	return nil
+}
+
+func (m *Envelope) GetAgeIdentity() *AgeIdentity {
+	if m != nil {
+		return m.AgeIdentity
+	}
+	return nil
+}
+
+func (m *Envelope) GetPKCS11Key() *PKCS11Key {
+	if m != nil {
+		return m.PKCS11Key
+	}
+	return nil
+}
+
 func (m *Envelope) Marshal() (data []byte, err error) {
 	size := m.Size()
 	data = make([]byte, size)
@@ -102,6 +147,56 @@ func (m *Envelope) MarshalTo(data []byte) (int, error) {
 		}
 		i += n3
 	}
+	if m.SSHAgent != nil {
+		data[i] = 0x22
+		i++
+		i = encodeVarintSecret(data, i, uint64(m.SSHAgent.Size()))
+		n4, err := m.SSHAgent.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if m.X25519Key != nil {
+		data[i] = 0x2a
+		i++
+		i = encodeVarintSecret(data, i, uint64(m.X25519Key.Size()))
+		n5, err := m.X25519Key.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	if m.HybridKEMKey != nil {
+		data[i] = 0x32
+		i++
+		i = encodeVarintSecret(data, i, uint64(m.HybridKEMKey.Size()))
+		n6, err := m.HybridKEMKey.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n6
+	}
+	if m.AgeIdentity != nil {
+		data[i] = 0x3a
+		i++
+		i = encodeVarintSecret(data, i, uint64(m.AgeIdentity.Size()))
+		n7, err := m.AgeIdentity.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
+	}
+	if m.PKCS11Key != nil {
+		data[i] = 0x42
+		i++
+		i = encodeVarintSecret(data, i, uint64(m.PKCS11Key.Size()))
+		n8, err := m.PKCS11Key.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
 	return i, nil
 }
 
@@ -147,6 +242,26 @@ func (m *Envelope) Size() (n int) {
 		l = m.SSHKey.Size()
 		n += 1 + l + sovSecret(uint64(l))
 	}
+	if m.SSHAgent != nil {
+		l = m.SSHAgent.Size()
+		n += 1 + l + sovSecret(uint64(l))
+	}
+	if m.X25519Key != nil {
+		l = m.X25519Key.Size()
+		n += 1 + l + sovSecret(uint64(l))
+	}
+	if m.HybridKEMKey != nil {
+		l = m.HybridKEMKey.Size()
+		n += 1 + l + sovSecret(uint64(l))
+	}
+	if m.AgeIdentity != nil {
+		l = m.AgeIdentity.Size()
+		n += 1 + l + sovSecret(uint64(l))
+	}
+	if m.PKCS11Key != nil {
+		l = m.PKCS11Key.Size()
+		n += 1 + l + sovSecret(uint64(l))
+	}
 	return n
 }
 
@@ -173,6 +288,21 @@ func (this *Envelope) GetValue() interface{} {
 	if this.SSHKey != nil {
 		return this.SSHKey
 	}
+	if this.SSHAgent != nil {
+		return this.SSHAgent
+	}
+	if this.X25519Key != nil {
+		return this.X25519Key
+	}
+	if this.HybridKEMKey != nil {
+		return this.HybridKEMKey
+	}
+	if this.AgeIdentity != nil {
+		return this.AgeIdentity
+	}
+	if this.PKCS11Key != nil {
+		return this.PKCS11Key
+	}
 	return nil
 }
 
@@ -184,6 +314,16 @@ func (this *Envelope) SetValue(value interface{}) bool {
 		this.OpenPGPKey = vt
 	case *SSHKey:
 		this.SSHKey = vt
+	case *SSHAgent:
+		this.SSHAgent = vt
+	case *X25519Key:
+		this.X25519Key = vt
+	case *HybridKEMKey:
+		this.HybridKEMKey = vt
+	case *AgeIdentity:
+		this.AgeIdentity = vt
+	case *PKCS11Key:
+		this.PKCS11Key = vt
 	default:
 		return false
 	}
@@ -298,6 +438,156 @@ func (m *Envelope) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SSHAgent", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthSecret
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SSHAgent == nil {
+				m.SSHAgent = &SSHAgent{}
+			}
+			if err := m.SSHAgent.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field X25519Key", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthSecret
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.X25519Key == nil {
+				m.X25519Key = &X25519Key{}
+			}
+			if err := m.X25519Key.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HybridKEMKey", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthSecret
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.HybridKEMKey == nil {
+				m.HybridKEMKey = &HybridKEMKey{}
+			}
+			if err := m.HybridKEMKey.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AgeIdentity", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthSecret
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.AgeIdentity == nil {
+				m.AgeIdentity = &AgeIdentity{}
+			}
+			if err := m.AgeIdentity.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PKCS11Key", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			postIndex := iNdEx + msglen
+			if msglen < 0 {
+				return ErrInvalidLengthSecret
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.PKCS11Key == nil {
+				m.PKCS11Key = &PKCS11Key{}
+			}
+			if err := m.PKCS11Key.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			var sizeOfWire int
 			for {
@@ -0,0 +1,127 @@
+package secret
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// sshAgentContext separates challenge signatures from other uses of the key.
+const sshAgentContext = "vcrypt ssh-agent challenge\x00"
+
+// NewSSHAgent constructs a new SSHAgent secret for the key held by an
+// ssh-agent with the fingerprint.
+func NewSSHAgent(fingerprint, comment string) (*SSHAgent, error) {
+	if _, _, err := parseFingerprint(fingerprint); err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 24)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return &SSHAgent{
+		fingerprint: fingerprint,
+		comment:     comment,
+		Nonce:       nonce,
+	}, nil
+}
+
+// Comment string
+func (s *SSHAgent) Comment() string {
+	return s.comment
+}
+
+// Phase is Dual
+func (s *SSHAgent) Phase() Phase { return Dual }
+
+// Challenge returns the data signed by the agent key.
+func (s *SSHAgent) Challenge() []byte {
+	return append([]byte(sshAgentContext), s.Nonce...)
+}
+
+// Match reports whether the fingerprint of pub matches the agent key.
+func (s *SSHAgent) Match(pub ssh.PublicKey) bool {
+	return matchFingerprint(s.fingerprint, pub)
+}
+
+// Sign signs the challenge with the matching key held by the agent and
+// returns the signature in SSH wire format. No data is returned if the agent
+// does not hold a matching key.
+func (s *SSHAgent) Sign(a agent.Agent) ([]byte, error) {
+	keys, err := a.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		if !s.Match(key) {
+			continue
+		}
+
+		var sig *ssh.Signature
+		switch key.Type() {
+		case ssh.KeyAlgoED25519:
+			sig, err = a.Sign(key, s.Challenge())
+		case ssh.KeyAlgoRSA:
+			ea, ok := a.(agent.ExtendedAgent)
+			if !ok {
+				return nil, errors.New("ssh-agent does not support rsa-sha2-256 signatures")
+			}
+			sig, err = ea.SignWithFlags(key, s.Challenge(), agent.SignatureFlagRsaSha256)
+		default:
+			return nil, fmt.Errorf("unsupported ssh-agent key type %q", key.Type())
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := key.Verify(s.Challenge(), sig); err != nil {
+			return nil, err
+		}
+		return ssh.Marshal(sig), nil
+	}
+
+	return nil, nil
+}
+
+// Load reads a challenge signature in SSH wire format and returns the key
+// derived from it. Only deterministic signature schemes are supported, so that
+// the same key is derived when locking & unlocking.
+func (s *SSHAgent) Load(r io.Reader) ([][]byte, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := &ssh.Signature{}
+	if err := ssh.Unmarshal(data, sig); err != nil {
+		return nil, err
+	}
+
+	switch sig.Format {
+	case ssh.KeyAlgoED25519, ssh.KeyAlgoRSASHA256:
+	default:
+		return nil, fmt.Errorf("unsupported ssh signature format %q", sig.Format)
+	}
+	if len(sig.Blob) == 0 {
+		return nil, errors.New("empty ssh signature")
+	}
+
+	// HKDF(signature, salt=Nonce, info=fingerprint)
+	kdf := hkdf.New(sha256.New, sig.Blob, s.Nonce, []byte(s.fingerprint))
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(kdf, key); err != nil {
+		return nil, err
+	}
+	return [][]byte{key}, nil
+}
@@ -0,0 +1,93 @@
+package secret
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func TestSSHAgent(t *testing.T) {
+	keyring := agent.NewKeyring()
+	for _, data := range []string{sshPrivate, sshEd25519Private, sshECDSAPrivate} {
+		key, err := ssh.ParseRawPrivateKey([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, fingerprint := range []string{sshFingerprint, sshEd25519Fingerprint} {
+		sec, err := NewSSHAgent(fingerprint, "test SSHAgent secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var keys [][]byte
+		for i := 0; i < 2; i++ {
+			sig, err := sec.Sign(keyring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sig == nil {
+				t.Fatalf("missing signature for %s", fingerprint)
+			}
+
+			data, err := sec.Load(bytes.NewBuffer(sig))
+			if err != nil {
+				t.Fatal(err)
+			}
+			keys = append(keys, data[0])
+		}
+
+		if len(keys[0]) != 32 {
+			t.Errorf("want 32 byte key, got %d bytes", len(keys[0]))
+		}
+		if !bytes.Equal(keys[0], keys[1]) {
+			t.Errorf("want equal keys for %s, got %x & %x", fingerprint, keys[0], keys[1])
+		}
+	}
+}
+
+func TestSSHAgentErrors(t *testing.T) {
+	keyring := agent.NewKeyring()
+	key, err := ssh.ParseRawPrivateKey([]byte(sshECDSAPrivate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+		t.Fatal(err)
+	}
+
+	sec, err := NewSSHAgent(sshEd25519Fingerprint, "test SSHAgent secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig, err := sec.Sign(keyring); sig != nil || err != nil {
+		t.Errorf("want no signature for missing key, got %x, %v", sig, err)
+	}
+
+	sec, err = NewSSHAgent(sshECDSAFingerprint, "test SSHAgent secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sec.Sign(keyring); err == nil {
+		t.Errorf("want unsupported key type error, got nil")
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := signer.Sign(rand.Reader, sec.Challenge())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sec.Load(bytes.NewBuffer(ssh.Marshal(sig))); err == nil {
+		t.Errorf("want unsupported signature format error, got nil")
+	}
+}
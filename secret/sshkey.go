@@ -2,8 +2,11 @@ package secret
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -34,43 +37,77 @@ func (s *SSHKey) Comment() string {
 // Phase is Unlock
 func (s *SSHKey) Phase() Phase { return Unlock }
 
+// Load parses & verifies an unencrypted private key in PEM or OpenSSH format.
+// RSA keys are returned PKCS #1 DER encoded, as the rsa cryptex expects, and
+// Ed25519 & ECDSA keys are returned PKCS #8 DER encoded.
 func (s *SSHKey) Load(r io.Reader) ([][]byte, error) {
 	data, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.verify(data); err != nil {
+	key, err := ssh.ParseRawPrivateKey(data)
+	if err != nil {
 		return nil, err
 	}
 
-	return [][]byte{data}, nil
+	return s.load(key)
 }
 
-func (s *SSHKey) verify(keyData []byte) error {
-	rawKey, err := ssh.ParseRawPrivateKey(keyData)
+// LoadWithPassphrase is Load for a passphrase protected private key.
+func (s *SSHKey) LoadWithPassphrase(r io.Reader, passphrase []byte) ([][]byte, error) {
+	data, err := ioutil.ReadAll(r)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	privKey, ok := rawKey.(*rsa.PrivateKey)
-	if !ok {
-		return errors.New("invalid ssh key, must be ssh-rsa")
+	key, err := ssh.ParseRawPrivateKeyWithPassphrase(data, passphrase)
+	if err != nil {
+		return nil, err
 	}
 
-	signer, err := ssh.NewSignerFromSigner(privKey)
-	if err != nil {
-		return err
+	return s.load(key)
+}
+
+func (s *SSHKey) load(key interface{}) ([][]byte, error) {
+	if err := s.verify(key); err != nil {
+		return nil, err
 	}
 
-	hash, fp, err := parseFingerprint(s.fingerprint)
+	var (
+		data []byte
+		err  error
+	)
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		data = x509.MarshalPKCS1PrivateKey(key)
+	case *ed25519.PrivateKey:
+		data, err = x509.MarshalPKCS8PrivateKey(*key)
+	case ed25519.PrivateKey, *ecdsa.PrivateKey:
+		data, err = x509.MarshalPKCS8PrivateKey(key)
+	default:
+		return nil, errors.New("invalid ssh key, must be ssh-rsa, ssh-ed25519, or ecdsa")
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if _, err := hash.Write(signer.PublicKey().Marshal()); err != nil {
+
+	return [][]byte{data}, nil
+}
+
+// Match reports whether the fingerprint of pub matches the SSH key.
+func (s *SSHKey) Match(pub ssh.PublicKey) bool {
+	return matchFingerprint(s.fingerprint, pub)
+}
+
+func (s *SSHKey) verify(key interface{}) error {
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
 		return err
 	}
-	if !bytes.Equal(fp, hash.Sum(nil)) {
+
+	if !s.Match(signer.PublicKey()) {
 		return errors.New("ssh key fingerprint mismatch")
 	}
 
@@ -95,3 +132,14 @@ func parseFingerprint(fingerprint string) (hash.Hash, []byte, error) {
 	fp, err := base64.StdEncoding.DecodeString(digest)
 	return sha256.New(), fp, err
 }
+
+func matchFingerprint(fingerprint string, pub ssh.PublicKey) bool {
+	hash, fp, err := parseFingerprint(fingerprint)
+	if err != nil {
+		return false
+	}
+	if _, err := hash.Write(pub.Marshal()); err != nil {
+		return false
+	}
+	return bytes.Equal(fp, hash.Sum(nil))
+}
@@ -0,0 +1,98 @@
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+const x25519KeyPEMType = "VCRYPT X25519 PRIVATE KEY"
+
+// NewX25519Key constructs a new X25519Key for the 32 byte Curve25519 public
+// key of a box cryptex.
+func NewX25519Key(publicKey []byte, comment string) (*X25519Key, error) {
+	if len(publicKey) != 32 {
+		return nil, errors.New("x25519 public key must be 32 bytes")
+	}
+
+	return &X25519Key{
+		PublicKey: publicKey,
+		comment:   comment,
+	}, nil
+}
+
+// Comment string
+func (s *X25519Key) Comment() string {
+	return s.comment
+}
+
+// Phase is Unlock
+func (s *X25519Key) Phase() Phase { return Unlock }
+
+// Load parses & verifies an unencrypted private key file and returns the raw
+// 32 byte private key, as the box cryptex expects.
+func (s *X25519Key) Load(r io.Reader) ([][]byte, error) {
+	return s.LoadWithPassphrase(r, nil)
+}
+
+// LoadWithPassphrase is Load for a passphrase protected private key file.
+func (s *X25519Key) LoadWithPassphrase(r io.Reader, passphrase []byte) ([][]byte, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := parseKeyFile(x25519KeyPEMType, data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, errors.New("invalid x25519 private key")
+	}
+
+	pub, err := curve25519.X25519(key, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Match(pub) {
+		return nil, errors.New("wrong private key for x25519 public key")
+	}
+
+	return [][]byte{key}, nil
+}
+
+// Match reports whether pub is the public key of the secret.
+func (s *X25519Key) Match(pub []byte) bool {
+	return bytes.Equal(s.PublicKey, pub)
+}
+
+// MarshalX25519PrivateKey returns the PEM encoded key file for a 32 byte
+// private key, encrypted unless the passphrase is empty.
+func MarshalX25519PrivateKey(key, passphrase []byte) ([]byte, error) {
+	if len(key) != 32 {
+		return nil, errors.New("x25519 private key must be 32 bytes")
+	}
+
+	pub, err := curve25519.X25519(key, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalKeyFile(x25519KeyPEMType, key, pub, passphrase)
+}
+
+// ParseX25519PublicKey returns the public key of a PEM encoded key file
+// without decrypting the private key.
+func ParseX25519PublicKey(data []byte) ([]byte, error) {
+	pub, err := parseKeyFilePublicKey(x25519KeyPEMType, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(pub) != 32 {
+		return nil, errors.New("invalid x25519 key file public key")
+	}
+	return pub, nil
+}
@@ -0,0 +1,76 @@
+package secret
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vcrypt/vcrypt/cryptex"
+	"golang.org/x/crypto/nacl/box"
+)
+
+func TestX25519Key(t *testing.T) {
+	pub, priv, err := box.GenerateKey(bytes.NewReader(bytes.Repeat([]byte{0x42}, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sec, err := NewX25519Key(pub[:], "test X25519Key secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, passphrase := range []string{"", "test passphrase"} {
+		data, err := MarshalX25519PrivateKey(priv[:], []byte(passphrase))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fpub, err := ParseX25519PublicKey(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sec.Match(fpub) {
+			t.Errorf("want key file public key %x, got %x", pub[:], fpub)
+		}
+
+		if passphrase != "" {
+			if _, err := sec.Load(bytes.NewReader(data)); err != ErrKeyFileEncrypted {
+				t.Errorf("want error %q, got %v", ErrKeyFileEncrypted, err)
+			}
+			if _, err := sec.LoadWithPassphrase(bytes.NewReader(data), []byte("wrong")); err == nil {
+				t.Errorf("want decryption error for wrong passphrase, got nil")
+			}
+		}
+
+		keys, err := sec.LoadWithPassphrase(bytes.NewReader(data), []byte(passphrase))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c := cryptex.NewBox(pub[:], "test box")
+		inputs := [][]byte{nil, nil}
+		if err := c.Close(inputs, [][]byte{[]byte("secret")}); err != nil {
+			t.Fatal(err)
+		}
+
+		secrets := [][]byte{nil}
+		if err := c.Open(secrets, [][]byte{inputs[0], keys[0]}); err != nil {
+			t.Fatal(err)
+		}
+		if want, got := []byte("secret"), secrets[0]; !bytes.Equal(want, got) {
+			t.Errorf("want box secret %q, got %q", want, got)
+		}
+	}
+
+	other, err := NewX25519Key(bytes.Repeat([]byte{0x01}, 32), "other key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := MarshalX25519PrivateKey(priv[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Load(bytes.NewReader(data)); err == nil {
+		t.Errorf("want public key mismatch error, got nil")
+	}
+}
@@ -0,0 +1,223 @@
+package vcrypt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/vcrypt/vcrypt/cryptex"
+	"github.com/vcrypt/vcrypt/graph"
+	"github.com/vcrypt/vcrypt/material"
+)
+
+// NodeState is the progress of a Node towards being solved for a vault.
+type NodeState int
+
+const (
+	// NodeSolved has material for its outputs. A secret node is solved once
+	// each of its parents is solved.
+	NodeSolved NodeState = iota + 1
+
+	// NodeReady has enough solved inputs to be solved by an unlock.
+	NodeReady
+
+	// NodeMissingSecret is a secret node required by an unsolved parent.
+	NodeMissingSecret
+
+	// NodePending has fewer solved inputs than required.
+	NodePending
+)
+
+func (s NodeState) String() string {
+	switch s {
+	case NodeSolved:
+		return "solved"
+	case NodeReady:
+		return "ready"
+	case NodeMissingSecret:
+		return "missing secret"
+	case NodePending:
+		return "pending"
+	default:
+		return "unknown"
+	}
+}
+
+// NodeStatus is the state of a plan Node for a vault.
+type NodeStatus struct {
+	*Node
+
+	ID    []byte
+	State NodeState
+
+	// Solved & Required count the inputs of a pending or ready node. Inputs
+	// of a WeightedSSS node are counted by weight. A VSS node requires K
+	// shares and its commitments material, so at most K shares are counted.
+	Solved, Required int
+}
+
+// Status returns the state of each plan node, in breadth-first order, for the
+// materials in the vault & db. No secrets are loaded.
+func (v *Vault) Status(db material.DB) ([]*NodeStatus, error) {
+	g, err := v.Plan.Graph()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[*graph.Vertex]*NodeStatus, len(g.Adjacency))
+	walker := func(vrt *graph.Vertex) error {
+		node, err := g.node(vrt)
+		if err != nil {
+			return err
+		}
+
+		id, err := node.Digest()
+		if err != nil {
+			return err
+		}
+
+		status := &NodeStatus{Node: node, ID: id}
+		statuses[vrt] = status
+
+		mtrl, err := db.LoadMaterial(id)
+		if err != nil {
+			return err
+		}
+		if mtrl != nil {
+			status.State = NodeSolved
+			return nil
+		}
+
+		switch node.Type() {
+		case SecretNode:
+			status.State = NodeMissingSecret
+		case MarkerNode:
+			status.State, status.Required = NodePending, 1
+			for _, mtrl := range v.Materials {
+				if bytes.Equal(id, mtrl.ID) {
+					status.State = NodeSolved
+				}
+			}
+		case CryptexNode:
+			cptx, err := node.Cryptex()
+			if err != nil {
+				return err
+			}
+
+			edges := g.Edges(vrt)
+			solved := make([]bool, len(edges))
+			for i, edge := range edges {
+				solved[i] = statuses[edge].State == NodeSolved
+			}
+
+			weights := inputWeights(cptx, len(edges))
+			status.Solved = solvedInputs(cptx, weights, solved)
+			status.State, status.Required = NodePending, requiredInputs(cptx, weights)
+			if status.Solved >= status.Required {
+				status.State = NodeReady
+			}
+		default:
+			return errors.New("unknown Node type")
+		}
+		return nil
+	}
+
+	if err := g.ReverseDFS(walker); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*NodeStatus, len(statuses))
+	for vrt, status := range statuses {
+		if status.State == NodeMissingSecret {
+			solved := true
+			for _, p := range g.parents(vrt) {
+				solved = solved && statuses[p].State == NodeSolved
+			}
+			if solved {
+				status.State = NodeSolved
+			}
+		}
+		byID[string(status.ID)] = status
+	}
+
+	list := make([]*NodeStatus, 0, len(statuses))
+	if err := v.Plan.BFS(func(node *Node) error {
+		id, err := node.Digest()
+		if err != nil {
+			return err
+		}
+
+		status, ok := byID[string(id)]
+		if !ok {
+			return fmt.Errorf("missing status for node %x", id)
+		}
+		list = append(list, status)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// inputWeights returns the weight of each of the n inputs to the cryptex.
+func inputWeights(cptx cryptex.Cryptex, n int) []int {
+	weights := make([]int, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+
+	if c, ok := cptx.(*cryptex.WeightedSSS); ok && len(c.Weights) == n {
+		for i, w := range c.Weights {
+			weights[i] = int(w)
+		}
+	}
+	return weights
+}
+
+// solvedInputs returns the total weight of the solved inputs to the cryptex.
+func solvedInputs(cptx cryptex.Cryptex, weights []int, solved []bool) int {
+	total := 0
+	for i, w := range weights {
+		if solved[i] {
+			total += w
+		}
+	}
+
+	// the last VSS input is the commitments material, required along with
+	// K shares
+	if c, ok := cptx.(*cryptex.VSS); ok && len(solved) > 0 {
+		material := solved[len(solved)-1]
+		if material {
+			total--
+		}
+		if total > int(c.K) {
+			total = int(c.K)
+		}
+		if material {
+			total++
+		}
+	}
+	return total
+}
+
+// requiredInputs returns the total input weight required to open the cryptex.
+func requiredInputs(cptx cryptex.Cryptex, weights []int) int {
+	switch c := cptx.(type) {
+	case *cryptex.SSS:
+		return int(c.K)
+	case *cryptex.VSS:
+		return int(c.K) + 1
+	case *cryptex.Threshold:
+		return int(c.K)
+	case *cryptex.WeightedSSS:
+		return int(c.Threshold)
+	case *cryptex.Mux:
+		return 1
+	}
+
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	return total
+}
@@ -2,11 +2,14 @@ package vcrypt
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	"io"
+	"sort"
 
 	"github.com/vcrypt/vcrypt/cryptex"
 	"github.com/vcrypt/vcrypt/graph"
@@ -32,12 +35,16 @@ func NewVault(plan *Plan, comment string) (*Vault, error) {
 
 // Lock encrypts a vault by building an encrypted Payload from r. It then
 // secures the decryption key in a multi-step encryption scheme described in
-// the Plan.
+// the Plan. The Plan seals are checked before any data is encrypted.
 func (v *Vault) Lock(r io.Reader, drv Driver) error {
 	if v.payload != nil {
 		return errors.New("Vault already locked")
 	}
 
+	if err := v.Plan.CheckSeals(); err != nil {
+		return err
+	}
+
 	pld, rootKey, err := drv.LockPayload(r)
 	if err != nil {
 		return err
@@ -48,13 +55,8 @@ func (v *Vault) Lock(r io.Reader, drv Driver) error {
 		return err
 	}
 
-	walker := &vaultWalker{
-		graph:   g,
-		drv:     drv,
-		outputs: map[*graph.Vertex][][]byte{g.Root: [][]byte{rootKey}},
-	}
-
-	if err := g.BFS(walker.lock); err != nil {
+	mtrls, err := v.close(g, [][]byte{rootKey}, drv)
+	if err != nil {
 		return err
 	}
 
@@ -64,41 +66,32 @@ func (v *Vault) Lock(r io.Reader, drv Driver) error {
 	}
 
 	v.payload = env
-	v.Materials = walker.materials
+	v.Materials = mtrls
 	return nil
 }
 
 // Unlock retrieves the Payload decryption key by solving the Plan and
-// writes the decrypted Payload data to w.
+// writes the decrypted Payload data to w. The Vault & Plan seals are checked
+// before any secrets are loaded.
 func (v *Vault) Unlock(w io.Writer, drv Driver) (unlocked bool, err error) {
 	if v.payload == nil {
 		return false, errors.New("Vault is not locked")
 	}
 
-	g, err := v.Plan.Graph()
-	if err != nil {
+	if err := v.CheckSeals(); err != nil {
 		return false, err
 	}
 
-	walker := &vaultWalker{
-		graph:     g,
-		drv:       drv,
-		materials: v.Materials,
-		outputs:   map[*graph.Vertex][][]byte{g.Root: [][]byte{nil}},
-		skipped:   map[*graph.Vertex]bool{},
-	}
-
-	if err := g.BFS(walker.shapeOutputs); err != nil {
+	g, err := v.Plan.Graph()
+	if err != nil {
 		return false, err
 	}
 
-	if err := g.ReverseDFS(walker.unlock); err != nil {
+	outputs, err := v.solve(g, g.Root, drv)
+	if err != nil || outputs == nil {
 		return false, err
 	}
-	if walker.skipped[g.Root] {
-		return false, nil
-	}
-	rootKey := walker.outputs[g.Root][0]
+	rootKey := outputs[0]
 
 	pld, err := v.Payload()
 	if err != nil {
@@ -112,6 +105,208 @@ func (v *Vault) Unlock(w io.Writer, drv Driver) (unlocked bool, err error) {
 	return true, nil
 }
 
+// Reshare replaces the cryptex node identified by the digest, or a unique
+// prefix of the digest, with the root node of the plan fragment. The node is
+// solved for its secrets which are then locked with the fragment, so the
+// payload key is unchanged. Nodes & materials below the replaced node are
+// dropped unless used elsewhere in the plan. The plan & vault seals are
+// removed as they no longer match. The fragment seals are checked before any
+// secrets are loaded.
+func (v *Vault) Reshare(nodeID []byte, fragment *Plan, drv Driver) error {
+	if v.payload == nil {
+		return errors.New("Vault is not locked")
+	}
+
+	if err := v.CheckSeals(); err != nil {
+		return err
+	}
+	if err := fragment.CheckSeals(); err != nil {
+		return err
+	}
+
+	g, err := v.Plan.Graph()
+	if err != nil {
+		return err
+	}
+
+	vrt, err := g.vertex(nodeID)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := v.solve(g, vrt, drv)
+	if err != nil {
+		return err
+	}
+	if secrets == nil {
+		return errors.New("could not solve node for resharing")
+	}
+
+	fg, err := fragment.Graph()
+	if err != nil {
+		return err
+	}
+
+	fmtrls, err := v.close(fg, secrets, drv)
+	if err != nil {
+		return err
+	}
+
+	if err := g.graft(vrt, fg); err != nil {
+		return err
+	}
+
+	nodes, err := g.Nodes()
+	if err != nil {
+		return err
+	}
+
+	markers := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if node.Type() != MarkerNode {
+			continue
+		}
+
+		fp, err := node.Digest()
+		if err != nil {
+			return err
+		}
+		markers[string(fp)] = true
+	}
+
+	mtrls := make([]*material.Material, 0, len(v.Materials)+len(fmtrls))
+	for _, mtrl := range v.Materials {
+		if markers[string(mtrl.ID)] {
+			mtrls = append(mtrls, mtrl)
+		}
+	}
+
+	v.Plan = &Plan{
+		comment: v.Plan.comment,
+		Nonce:   v.Plan.Nonce,
+		Nodes:   nodes,
+	}
+	v.Materials = append(mtrls, fmtrls...)
+	v.seals = nil
+	return nil
+}
+
+// Rekey moves the vault to a new Plan. The payload key is recovered by
+// solving the current plan and then locked with the new plan, the payload is
+// not decrypted. The new Plan seals are checked before any secrets are loaded
+// and the vault seals are removed as they no longer match.
+func (v *Vault) Rekey(plan *Plan, drv Driver) error {
+	if v.payload == nil {
+		return errors.New("Vault is not locked")
+	}
+
+	if err := v.CheckSeals(); err != nil {
+		return err
+	}
+	if err := plan.CheckSeals(); err != nil {
+		return err
+	}
+
+	g, err := v.Plan.Graph()
+	if err != nil {
+		return err
+	}
+
+	outputs, err := v.solve(g, g.Root, drv)
+	if err != nil {
+		return err
+	}
+	if outputs == nil {
+		return errors.New("could not solve plan for rekeying")
+	}
+
+	ng, err := plan.Graph()
+	if err != nil {
+		return err
+	}
+
+	mtrls, err := v.close(ng, outputs, drv)
+	if err != nil {
+		return err
+	}
+
+	v.Plan = plan
+	v.Materials = mtrls
+	v.seals = nil
+	return nil
+}
+
+// AddSeal adds a Seal for the locked Vault from the nonce, plan, materials,
+// payload, and comment data.
+func (v *Vault) AddSeal(slr Sealer) (seal.Seal, error) {
+	data, err := v.sealData()
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := slr.Seal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := seal.Wrap(s)
+	if err != nil {
+		return nil, err
+	}
+
+	v.seals = append(v.seals, env)
+	return s, nil
+}
+
+// CheckSeals verifies the Vault seals and the Plan seals.
+func (v *Vault) CheckSeals() error {
+	if err := v.Plan.CheckSeals(); err != nil {
+		return err
+	}
+
+	if len(v.seals) == 0 {
+		return nil
+	}
+
+	data, err := v.sealData()
+	if err != nil {
+		return err
+	}
+
+	seals, err := v.Seals()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range seals {
+		if err := s.Check(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckSealPolicy checks the vault & plan seals and returns a
+// seal.PolicyError unless they satisfy the policy. Seals on the plan and on
+// the vault both count towards the threshold.
+func (v *Vault) CheckSealPolicy(pol *seal.Policy) error {
+	if err := v.CheckSeals(); err != nil {
+		return err
+	}
+
+	seals, err := v.Plan.Seals()
+	if err != nil {
+		return err
+	}
+
+	vseals, err := v.Seals()
+	if err != nil {
+		return err
+	}
+
+	return pol.Check(append(seals, vseals...))
+}
+
 // Comment string
 func (v *Vault) Comment() string {
 	return v.comment
@@ -189,6 +384,83 @@ func (v *Vault) Payload() (payload.Payload, error) {
 	return v.payload.Payload()
 }
 
+func (v *Vault) sealData() ([]byte, error) {
+	if v.payload == nil {
+		return nil, errors.New("unlocked vault has no seal data")
+	}
+
+	// Nonce|Plan.Digest|Materials[*].Digest|Payload.Digest|Comment
+	data := append([]byte{}, v.Nonce...)
+
+	fp, err := v.Plan.Digest()
+	if err != nil {
+		return nil, err
+	}
+	data = append(data, fp...)
+
+	for _, m := range v.Materials {
+		if fp, err = m.Digest(); err != nil {
+			return nil, err
+		}
+		data = append(data, fp...)
+	}
+
+	pld, err := v.Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	if fp, err = pld.Digest(); err != nil {
+		return nil, err
+	}
+	data = append(data, fp...)
+
+	return append(data, []byte(v.comment)...), nil
+}
+
+// close locks the secrets with the graph and returns the graph materials.
+func (v *Vault) close(g *Graph, secrets [][]byte, drv Driver) ([]*material.Material, error) {
+	walker := &vaultWalker{
+		graph:   g,
+		drv:     drv,
+		outputs: map[*graph.Vertex][][]byte{g.Root: secrets},
+		derived: map[*graph.Vertex][][]byte{},
+	}
+
+	if err := g.ReverseDFS(walker.derive); err != nil {
+		return nil, err
+	}
+	if err := g.BFS(walker.lock); err != nil {
+		return nil, err
+	}
+	return walker.materials, nil
+}
+
+// solve unlocks the subgraph below vrt and returns the vertex outputs, or nil
+// if a required secret was skipped.
+func (v *Vault) solve(g *Graph, vrt *graph.Vertex, drv Driver) ([][]byte, error) {
+	walker := &vaultWalker{
+		graph:     g,
+		drv:       drv,
+		materials: v.Materials,
+		outputs:   map[*graph.Vertex][][]byte{g.Root: [][]byte{nil}},
+		skipped:   map[*graph.Vertex]bool{},
+		order:     map[*graph.Vertex]int{},
+	}
+
+	if err := g.BFS(walker.shapeOutputs); err != nil {
+		return nil, err
+	}
+
+	if err := g.ReverseDFSFrom(vrt, walker.unlock); err != nil {
+		return nil, err
+	}
+	if walker.skipped[vrt] {
+		return nil, nil
+	}
+	return walker.outputs[vrt], nil
+}
+
 type vaultWalker struct {
 	graph *Graph
 	drv   Driver
@@ -197,6 +469,50 @@ type vaultWalker struct {
 
 	outputs map[*graph.Vertex][][]byte
 	skipped map[*graph.Vertex]bool
+
+	// keys of Derive vertexes, one per parent edge
+	derived map[*graph.Vertex][][]byte
+
+	// BFS order of the vertexes, which is the order parents lock & append
+	// their outputs to a shared child
+	order map[*graph.Vertex]int
+}
+
+// derive locks a Derive vertex ahead of its parents, which then close with the
+// derived keys as inputs.
+func (w *vaultWalker) derive(vrt *graph.Vertex) error {
+	node, err := w.graph.node(vrt)
+	if err != nil {
+		return err
+	}
+	if node.Type() != CryptexNode {
+		return nil
+	}
+
+	cptx, err := node.Cryptex()
+	if err != nil {
+		return err
+	}
+	if _, ok := cptx.(*cryptex.Derive); !ok {
+		return nil
+	}
+	if vrt == w.graph.Root {
+		return errors.New("Derive cryptex can not be the root node")
+	}
+
+	parents, err := w.graph.parentEdges(vrt)
+	if err != nil {
+		return err
+	}
+
+	w.outputs[vrt] = make([][]byte, len(parents))
+	mtrl, err := w.lockCryptex(cptx, vrt)
+	if err != nil {
+		return err
+	}
+
+	w.derived[vrt], w.outputs[vrt] = w.outputs[vrt], nil
+	return w.drv.StoreMaterial(mtrl)
 }
 
 func (w *vaultWalker) lock(vrt *graph.Vertex) error {
@@ -213,6 +529,10 @@ func (w *vaultWalker) lock(vrt *graph.Vertex) error {
 	var mtrl *material.Material
 	switch node.Type() {
 	case CryptexNode:
+		if _, ok := w.derived[vrt]; ok {
+			return nil
+		}
+
 		cptx, err := node.Cryptex()
 		if err != nil {
 			return err
@@ -251,16 +571,24 @@ func (w *vaultWalker) lock(vrt *graph.Vertex) error {
 func (w *vaultWalker) lockCryptex(cptx cryptex.Cryptex, vrt *graph.Vertex) (*material.Material, error) {
 	edges := w.graph.Edges(vrt)
 	inputs := make([][]byte, 0, w.graph.Adjacency[vrt].Len())
+	keys := make([][]byte, len(edges))
 
-	for _, vrt := range edges {
-		node, err := w.graph.node(vrt)
+	for i, child := range edges {
+		node, err := w.graph.node(child)
 		if err != nil {
 			return nil, err
 		}
 
 		switch node.Type() {
 		case CryptexNode, MarkerNode:
-			inputs = append(inputs, nil)
+			if derived, ok := w.derived[child]; ok {
+				idx, err := w.deriveIndex(child, vrt, edges[:i])
+				if err != nil {
+					return nil, err
+				}
+				keys[i] = derived[idx]
+			}
+			inputs = append(inputs, keys[i])
 		case SecretNode:
 			sec, err := node.Secret()
 			if err != nil {
@@ -269,12 +597,12 @@ func (w *vaultWalker) lockCryptex(cptx cryptex.Cryptex, vrt *graph.Vertex) (*mat
 
 			data, skip := [][]byte{[]byte{}}, false
 			if sec.Phase() == secret.Dual {
-				if data, skip, err = w.drv.LoadSecret(sec); err != nil {
+				if data, skip, err = w.loadSecret(sec, child, true); err != nil {
 					return nil, err
 				}
 			}
 			if skip {
-				w.skipped[vrt] = true
+				return nil, fmt.Errorf("secret %q is required to lock", sec.Comment())
 			}
 
 			inputs = append(inputs, data...)
@@ -289,6 +617,9 @@ func (w *vaultWalker) lockCryptex(cptx cryptex.Cryptex, vrt *graph.Vertex) (*mat
 	}
 
 	for i, vrt := range edges {
+		if keys[i] != nil && !bytes.Equal(keys[i], inputs[i]) {
+			return nil, errors.New("cryptex does not accept a derived key input")
+		}
 		w.outputs[vrt] = append(w.outputs[vrt], inputs[i])
 	}
 
@@ -306,6 +637,7 @@ func (w *vaultWalker) lockCryptex(cptx cryptex.Cryptex, vrt *graph.Vertex) (*mat
 }
 
 func (w *vaultWalker) shapeOutputs(vrt *graph.Vertex) error {
+	w.order[vrt] = len(w.order)
 	for _, v := range w.graph.Edges(vrt) {
 		w.outputs[v] = append(w.outputs[v], nil)
 	}
@@ -351,7 +683,7 @@ func (w *vaultWalker) unlock(vrt *graph.Vertex) error {
 			return err
 		}
 
-		output, skip, err := w.drv.LoadSecret(sec)
+		output, skip, err := w.loadSecret(sec, vrt, false)
 		if err != nil {
 			return err
 		}
@@ -382,19 +714,21 @@ func (w *vaultWalker) unlockCryptex(cptx cryptex.Cryptex, vrt *graph.Vertex) (*m
 	inputs := make([][]byte, 0, w.graph.Adjacency[vrt].Len())
 
 	skippable := false
-	for _, vrt := range edges {
-		inputs = append(inputs, w.outputs[vrt][0])
-		if len(w.outputs[vrt]) > 1 {
-			w.outputs[vrt] = w.outputs[vrt][1:]
-		} else {
-			w.outputs[vrt] = [][]byte{}
+	for i, child := range edges {
+		idx, err := w.outputIndex(child, vrt, edges[:i])
+		if err != nil {
+			return nil, err
 		}
+		if idx >= len(w.outputs[child]) {
+			return nil, errors.New("missing output for parent edge")
+		}
+		inputs = append(inputs, w.outputs[child][idx])
 
-		if w.skipped[vrt] {
+		if w.skipped[child] {
 			skippable = true
 		}
 	}
-	if err := cptx.Open(w.outputs[vrt], inputs); err != nil {
+	if err := w.open(cptx, w.outputs[vrt], inputs); err != nil {
 		if skippable {
 			w.skipped[vrt] = true
 			// TODO w.drv.Warn(err)
@@ -416,6 +750,117 @@ func (w *vaultWalker) unlockCryptex(cptx cryptex.Cryptex, vrt *graph.Vertex) (*m
 	return material.New(id, w.outputs[vrt])
 }
 
+// isDerive reports whether vrt is a Derive cryptex vertex.
+func (w *vaultWalker) isDerive(vrt *graph.Vertex) (bool, error) {
+	node, err := w.graph.node(vrt)
+	if err != nil {
+		return false, err
+	}
+	if node.Type() != CryptexNode {
+		return false, nil
+	}
+
+	cptx, err := node.Cryptex()
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := cptx.(*cryptex.Derive)
+	return ok, nil
+}
+
+// deriveIndex returns the index of the derived key passed from the Derive
+// vertex to the parent, for the edge following the parent's prior edges.
+// Derived keys are bound to parent edges in parent digest order so that lock
+// & unlock agree regardless of walk order.
+func (w *vaultWalker) deriveIndex(vrt, parent *graph.Vertex, prior []*graph.Vertex) (int, error) {
+	parents, err := w.graph.parentEdges(vrt)
+	if err != nil {
+		return 0, err
+	}
+	return edgeIndex(parents, vrt, parent, prior)
+}
+
+// outputIndex returns the index of the output passed from vrt to the parent,
+// for the edge following the parent's prior edges. Outputs other than derived
+// keys are appended by each parent in BFS order during lock.
+func (w *vaultWalker) outputIndex(vrt, parent *graph.Vertex, prior []*graph.Vertex) (int, error) {
+	derived, err := w.isDerive(vrt)
+	if err != nil {
+		return 0, err
+	}
+	if derived {
+		return w.deriveIndex(vrt, parent, prior)
+	}
+
+	var parents []*graph.Vertex
+	for _, p := range w.graph.parents(vrt) {
+		if _, ok := w.order[p]; ok {
+			parents = append(parents, p)
+		}
+	}
+	sort.SliceStable(parents, func(i, j int) bool {
+		return w.order[parents[i]] < w.order[parents[j]]
+	})
+	return edgeIndex(parents, vrt, parent, prior)
+}
+
+// edgeIndex returns the index in parents, one entry per parent edge, of the
+// edge from parent to vrt following the parent's prior edges.
+func edgeIndex(parents []*graph.Vertex, vrt, parent *graph.Vertex, prior []*graph.Vertex) (int, error) {
+	n := 0
+	for _, v := range prior {
+		if v == vrt {
+			n++
+		}
+	}
+
+	for i, p := range parents {
+		if p == parent {
+			if i+n >= len(parents) || parents[i+n] != parent {
+				break
+			}
+			return i + n, nil
+		}
+	}
+	return 0, errors.New("missing parent edge to vertex")
+}
+
+// loadSecret loads the secret data for the secret vertex, with the node
+// position if the driver is a NodeDriver.
+func (w *vaultWalker) loadSecret(sec secret.Secret, vrt *graph.Vertex, lock bool) ([][]byte, bool, error) {
+	ndrv, ok := w.drv.(NodeDriver)
+	if !ok {
+		return w.drv.LoadSecret(sec)
+	}
+
+	pos, err := w.graph.position(vrt)
+	if err != nil {
+		return nil, false, err
+	}
+	pos.Lock = lock
+	return ndrv.LoadNodeSecret(sec, pos)
+}
+
+func (w *vaultWalker) open(cptx cryptex.Cryptex, secrets, inputs [][]byte) error {
+	co, ok := cptx.(cryptex.ContextOpener)
+	if !ok {
+		return cptx.Open(secrets, inputs)
+	}
+
+	ctx, progress := context.Background(), func(done, total uint64) {}
+	if cd, ok := w.drv.(ContextDriver); ok {
+		var stop context.CancelFunc
+		ctx, stop = cd.Context()
+		defer stop()
+
+		progress = func(done, total uint64) {
+			cd.Progress(cptx, done, total)
+		}
+	}
+	return co.OpenContext(ctx, progress, secrets, inputs)
+}
+
 func (w *vaultWalker) material(id []byte) (*material.Material, error) {
 	for _, mtrl := range w.materials {
 		if bytes.Equal(id, mtrl.ID) {
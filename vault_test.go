@@ -2,11 +2,18 @@ package vcrypt
 
 import (
 	"bytes"
+	"context"
+	"crypto/ed25519"
 	"crypto/rand"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"reflect"
 	"testing"
 
+	"github.com/vcrypt/vcrypt/cryptex"
 	"github.com/vcrypt/vcrypt/internal/test"
+	"github.com/vcrypt/vcrypt/seal"
 	"github.com/vcrypt/vcrypt/secret"
 	"golang.org/x/crypto/openpgp"
 )
@@ -131,6 +138,280 @@ func TestMultiPassVault(t *testing.T) {
 	}
 }
 
+func TestVaultLockSkippedSecret(t *testing.T) {
+	vault, err := NewVault(twoPartyPlan, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drv := skipDriver{Driver: test.Driver{
+		"party 1 password 2": twoPartyDriver["party 1 password 2"],
+		"party 1 password 1": twoPartyDriver["party 1 password 1"],
+	}}
+
+	err = vault.Lock(bytes.NewBufferString("secret"), drv)
+	if want := `secret "party 2 password" is required to lock`; err == nil || err.Error() != want {
+		t.Errorf("want lock error %q, got %v", want, err)
+	}
+}
+
+func TestVaultSeal(t *testing.T) {
+	vault, secret := buildVault(buildPlan(twoManGraph, "two-man rule plan"), twoManDriver)
+
+	ufp, err := vault.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := vault.AddSeal(test.Sealer); err != nil {
+		t.Fatal(err)
+	}
+	if err := vault.CheckSeals(); err != nil {
+		t.Fatal(err)
+	}
+
+	sfp, err := vault.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(ufp, sfp) {
+		t.Errorf("vault digest unchanged by seal")
+	}
+
+	var got bytes.Buffer
+	if _, err := vault.Unlock(&got, twoManDriver); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(secret, got.Bytes()) {
+		t.Errorf("vault unlocked bad secret: want %v, got %v", secret, got.Bytes())
+	}
+
+	vault.comment = "tampered vault comment"
+	if err := vault.CheckSeals(); err == nil {
+		t.Errorf("want seal check error for tampered vault, got nil")
+	}
+	if _, err := vault.Unlock(&got, twoManDriver); err == nil {
+		t.Errorf("want unlock error for tampered vault, got nil")
+	}
+}
+
+func TestVaultSealPolicy(t *testing.T) {
+	plan := buildPlan(twoManGraph, "two-man rule plan")
+	pseal, err := plan.AddSeal(test.Sealer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pids, err := seal.Identities(pseal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vault, _ := buildVault(plan, twoManDriver)
+
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{0x42}, ed25519.SeedSize))
+	vseal, err := vault.AddSeal(seal.NewEd25519Sealer(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vids, err := seal.Identities(vseal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pol := &seal.Policy{
+		Threshold: 2,
+		Signers: []seal.Signer{
+			{Name: "planner", IDs: pids},
+			{Name: "locker", IDs: vids},
+			{Name: "auditor", IDs: []string{"0123456789ABCDEF"}},
+		},
+	}
+
+	if err := vault.CheckSealPolicy(pol); err != nil {
+		t.Error(err)
+	}
+
+	err = plan.CheckSealPolicy(pol)
+	perr, ok := err.(*seal.PolicyError)
+	if !ok {
+		t.Fatalf("want PolicyError for plan seals, got %v", err)
+	}
+	if want := []string{"locker", "auditor"}; !reflect.DeepEqual(want, perr.Missing) {
+		t.Errorf("want missing signers %v, got %v", want, perr.Missing)
+	}
+}
+
+func TestVaultReshare(t *testing.T) {
+	plan, err := BuildPlan(bytes.NewBufferString(`
+root = top
+
+[secretbox "top"]
+edge = quorum
+edge = top material
+
+[sss "quorum"]
+max-shares = 3
+required-shares = 2
+edge = alice box
+edge = bob box
+edge = claire box
+
+[secretbox "alice box"]
+edge = alice
+edge = alice material
+
+[secretbox "bob box"]
+edge = bob
+edge = bob material
+
+[secretbox "claire box"]
+edge = claire
+edge = claire material
+
+[password "alice"]
+[password "bob"]
+[password "claire"]
+
+[material "top material"]
+[material "alice material"]
+[material "bob material"]
+[material "claire material"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fragment, err := BuildPlan(bytes.NewBufferString(`
+root = quorum
+
+[xor "quorum"]
+edge = bob box
+edge = david box
+
+[secretbox "bob box"]
+edge = bob
+edge = bob material
+
+[secretbox "david box"]
+edge = david
+edge = david material
+
+[password "bob"]
+[password "david"]
+
+[material "bob material"]
+[material "david material"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drv := test.Driver{
+		"alice":  []byte("alice password"),
+		"bob":    []byte("bob password"),
+		"claire": []byte("claire password"),
+		"david":  []byte("david password"),
+	}
+	vault, secret := buildVault(plan, drv)
+
+	var quorumID []byte
+	err = plan.BFS(func(node *Node) error {
+		if cmnt, _ := node.Comment(); cmnt == "quorum" {
+			quorumID, err = node.Digest()
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fragment.AddSeal(test.Sealer); err != nil {
+		t.Fatal(err)
+	}
+	fragment.comment = "tampered fragment comment"
+	if err := vault.Reshare(quorumID[:8], fragment, sealCheckDriver{t: t}); err == nil {
+		t.Errorf("want reshare error for tampered fragment, got nil")
+	}
+	fragment.comment = ""
+
+	// solve the quorum with alice & bob only
+	if err := vault.Reshare(quorumID[:8], fragment, skipDriver{Driver: test.Driver{
+		"alice": drv["alice"],
+		"bob":   drv["bob"],
+		"david": drv["david"],
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 3, len(vault.Materials); want != got {
+		t.Errorf("want %d materials after reshare, got %d", want, got)
+	}
+
+	var got bytes.Buffer
+	if ok, err := vault.Unlock(&got, test.Driver{"bob": drv["bob"], "david": drv["david"]}); err != nil || !ok {
+		t.Fatalf("want unlock by bob & david, got %v, %v", ok, err)
+	}
+	if !bytes.Equal(secret, got.Bytes()) {
+		t.Errorf("vault unlocked bad secret: want %v, got %v", secret, got.Bytes())
+	}
+
+	if ok, err := vault.Unlock(&got, skipDriver{Driver: test.Driver{"alice": drv["alice"], "bob": drv["bob"]}}); err != nil || ok {
+		t.Errorf("want vault locked to alice & bob after reshare, got %v, %v", ok, err)
+	}
+}
+
+// sealCheckDriver fails the test if a secret is loaded.
+type sealCheckDriver struct {
+	test.Driver
+
+	t *testing.T
+}
+
+func (d sealCheckDriver) LoadSecret(sec secret.Secret) ([][]byte, bool, error) {
+	d.t.Errorf("secret %q loaded before the seal check", sec.Comment())
+	return [][]byte{[]byte{}}, true, nil
+}
+
+func TestVaultRekey(t *testing.T) {
+	passwords := map[string][]byte{
+		"op 1 secret":      []byte("key #1"),
+		"op 2 secret":      []byte("key #2"),
+		"step 3 password":  []byte("step #3 password"),
+		"step 2a password": []byte("step #2a password"),
+		"step 2b password": []byte("step #2b password"),
+		"step 1 password":  []byte("step #1 password"),
+	}
+	driver := func(names ...string) test.Driver {
+		drv := test.Driver{}
+		for _, name := range names {
+			drv[name] = passwords[name]
+		}
+		return drv
+	}
+
+	vault, secret := buildVault(buildPlan(twoManGraph, "two-man rule plan"), driver("op 1 secret", "op 2 secret"))
+	pld := vault.payload
+
+	if err := vault.Rekey(diamondPlan, skipDriver{Driver: driver("op 1 secret")}); err == nil {
+		t.Errorf("want rekey error without secrets, got nil")
+	}
+
+	drv := driver("op 1 secret", "op 2 secret", "step 3 password", "step 2a password", "step 2b password", "step 1 password")
+	if err := vault.Rekey(diamondPlan, drv); err != nil {
+		t.Fatal(err)
+	}
+	if vault.payload != pld {
+		t.Errorf("rekey changed the vault payload")
+	}
+
+	var got bytes.Buffer
+	if ok, err := vault.Unlock(&got, skipDriver{Driver: driver("step 3 password", "step 2b password", "step 1 password")}); err != nil || !ok {
+		t.Fatalf("want unlock with new plan secrets, got %v, %v", ok, err)
+	}
+	if !bytes.Equal(secret, got.Bytes()) {
+		t.Errorf("vault unlocked bad secret: want %v, got %v", secret, got.Bytes())
+	}
+}
+
 func buildVault(plan *Plan, drv Driver) (*Vault, []byte) {
 	secret := make([]byte, 256)
 	if _, err := io.ReadFull(rand.Reader, secret); err != nil {
@@ -160,3 +441,539 @@ func mustOpenPGPKey(keyPEM string) []byte {
 	}
 	return buf.Bytes()
 }
+
+func TestVaultDerive(t *testing.T) {
+	plan, err := BuildPlan(bytes.NewBufferString(`
+root = both
+
+[xor "both"]
+edge = payroll box
+edge = backups box
+
+[secretbox "payroll box"]
+edge = master
+edge = payroll material
+
+[secretbox "backups box"]
+edge = master
+edge = backups material
+
+[derive "master"]
+label = payroll
+label = backups
+edge = master password
+
+[password "master password"]
+
+[material "payroll material"]
+[material "backups material"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drv := test.Driver{"master password": []byte("master password")}
+	vault, secret := buildVault(plan, drv)
+
+	// only the secretbox materials are stored
+	if want, got := 2, len(vault.Materials); want != got {
+		t.Errorf("want %d materials, got %d", want, got)
+	}
+
+	var got bytes.Buffer
+	if ok, err := vault.Unlock(&got, test.Driver{"master password": []byte("master password")}); err != nil || !ok {
+		t.Fatalf("want unlock with master password, got %v, %v", ok, err)
+	}
+	if !bytes.Equal(secret, got.Bytes()) {
+		t.Errorf("vault unlocked bad secret: want %v, got %v", secret, got.Bytes())
+	}
+
+	// parents of the derive node at different depths
+	plan, err = BuildPlan(bytes.NewBufferString(`
+root = both
+
+[xor "both"]
+edge = b box
+edge = p box
+
+[secretbox "p box"]
+edge = a box
+edge = p material
+
+[secretbox "a box"]
+edge = master
+edge = a material
+
+[secretbox "b box"]
+edge = master
+edge = b material
+
+[derive "master"]
+label = a
+label = b
+edge = master password
+
+[password "master password"]
+
+[material "a material"]
+[material "b material"]
+[material "p material"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vault, secret = buildVault(plan, drv)
+
+	got.Reset()
+	if ok, err := vault.Unlock(&got, test.Driver{"master password": []byte("master password")}); err != nil || !ok {
+		t.Fatalf("want unlock with master password, got %v, %v", ok, err)
+	}
+	if !bytes.Equal(secret, got.Bytes()) {
+		t.Errorf("vault unlocked bad secret: want %v, got %v", secret, got.Bytes())
+	}
+
+	plan, err = BuildPlan(bytes.NewBufferString(`
+root = quorum
+
+[sss "quorum"]
+max-shares = 2
+required-shares = 2
+edge = master
+edge = other password
+
+[derive "master"]
+label = quorum
+edge = master password
+
+[password "master password"]
+[password "other password"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vault, err = NewVault(plan, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	drv = test.Driver{"master password": []byte("master password"), "other password": []byte("other password")}
+	if err := vault.Lock(bytes.NewBufferString("secret"), drv); err == nil {
+		t.Errorf("want lock error for sss with a derived key input, got nil")
+	}
+}
+
+func TestVaultAccessPolicy(t *testing.T) {
+	passwords := map[string][]byte{
+		"alice":  []byte("alice password"),
+		"bob":    []byte("bob password"),
+		"claire": []byte("claire password"),
+		"david":  []byte("david password"),
+	}
+	driver := func(names ...string) Driver {
+		drv := test.Driver{}
+		for _, name := range names {
+			drv[name] = passwords[name]
+		}
+		return skipDriver{Driver: drv}
+	}
+
+	policies := []struct {
+		formula string
+		tests   []struct {
+			names []string
+			ok    bool
+		}
+	}{
+		{
+			formula: "(alice box and bob box) or (2 of alice box, claire box, david box)",
+			tests: []struct {
+				names []string
+				ok    bool
+			}{
+				{[]string{"alice", "bob"}, true},
+				{[]string{"claire", "david"}, true},
+				{[]string{"alice", "claire"}, true},
+				{[]string{"bob", "claire"}, false},
+				{[]string{"david"}, false},
+			},
+		},
+		// alice box is shared by parents at different depths
+		{
+			formula: "alice box or (bob box and (alice box or claire box))",
+			tests: []struct {
+				names []string
+				ok    bool
+			}{
+				{[]string{"alice"}, true},
+				{[]string{"bob", "claire"}, true},
+				{[]string{"bob", "alice"}, true},
+				{[]string{"bob"}, false},
+				{[]string{"claire", "david"}, false},
+			},
+		},
+	}
+
+	for _, policy := range policies {
+		plan, err := BuildPlan(bytes.NewBufferString(`
+root = access
+
+[policy "access"]
+formula = ` + policy.formula + `
+
+[secretbox "alice box"]
+edge = alice
+edge = alice material
+
+[secretbox "bob box"]
+edge = bob
+edge = bob material
+
+[secretbox "claire box"]
+edge = claire
+edge = claire material
+
+[secretbox "david box"]
+edge = david
+edge = david material
+
+[password "alice"]
+[password "bob"]
+[password "claire"]
+[password "david"]
+
+[material "alice material"]
+[material "bob material"]
+[material "claire material"]
+[material "david material"]
+`))
+		if err != nil {
+			t.Fatalf("%s: %s", policy.formula, err)
+		}
+
+		vault, secret := buildVault(plan, driver("alice", "bob", "claire", "david"))
+
+		for _, test := range policy.tests {
+			var got bytes.Buffer
+			ok, err := vault.Unlock(&got, driver(test.names...))
+			if err != nil {
+				t.Fatalf("%s: %v: %s", policy.formula, test.names, err)
+			}
+			if ok != test.ok {
+				t.Errorf("%s: %v: want unlock %t, got %t", policy.formula, test.names, test.ok, ok)
+			}
+			if ok && !bytes.Equal(secret, got.Bytes()) {
+				t.Errorf("%s: %v: vault unlocked bad secret: want %v, got %v", policy.formula, test.names, secret, got.Bytes())
+			}
+		}
+	}
+}
+
+// nodeDriver loads secrets by node ID.
+type nodeDriver struct {
+	test.Driver
+
+	secrets   map[string][]byte
+	positions map[string]NodePosition
+}
+
+func (d nodeDriver) LoadNodeSecret(sec secret.Secret, pos NodePosition) ([][]byte, bool, error) {
+	d.positions[string(pos.ID)] = pos
+
+	data, ok := d.secrets[string(pos.ID)]
+	if !ok {
+		return [][]byte{[]byte{}}, true, nil
+	}
+
+	datas, err := sec.Load(bytes.NewBuffer(data))
+	return datas, false, err
+}
+
+func TestVaultNodeDriver(t *testing.T) {
+	plan, err := BuildPlan(bytes.NewBufferString(`
+root = both
+
+[xor "both"]
+edge = first box
+edge = second box
+
+[secretbox "first box"]
+edge = first password
+edge = first material
+
+[secretbox "second box"]
+edge = second password
+edge = second material
+
+[password "first password"]
+comment = operator password
+
+[password "second password"]
+comment = operator password
+
+[material "first material"]
+[material "second material"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// parent box node id by password node id
+	passwords := map[string][]byte{}
+	for _, node := range plan.Nodes {
+		id, err := node.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, input := range node.Inputs {
+			for _, child := range plan.Nodes {
+				if cid, _ := child.Digest(); bytes.Equal(cid, input) && child.Type() == SecretNode {
+					passwords[string(input)] = id
+				}
+			}
+		}
+	}
+	if want, got := 2, len(passwords); want != got {
+		t.Fatalf("want %d password nodes, got %d", want, got)
+	}
+
+	drv := nodeDriver{
+		Driver:    test.Driver{},
+		secrets:   map[string][]byte{},
+		positions: map[string]NodePosition{},
+	}
+	for id := range passwords {
+		drv.secrets[id] = []byte("password for " + id)
+	}
+	vault, secret := buildVault(plan, drv)
+
+	for id, box := range passwords {
+		pos, ok := drv.positions[id]
+		if !ok {
+			t.Fatalf("want password loaded for node %x", id)
+		}
+		if want, got := [][]byte{box}, pos.Parents; !reflect.DeepEqual(want, got) {
+			t.Errorf("want parents %x, got %x", want, got)
+		}
+		if want, got := 2, pos.Depth; want != got {
+			t.Errorf("want depth %d, got %d", want, got)
+		}
+		if !pos.Lock {
+			t.Errorf("want lock position for node %x", id)
+		}
+	}
+
+	unlockDrv := nodeDriver{
+		Driver:    test.Driver{},
+		secrets:   drv.secrets,
+		positions: map[string]NodePosition{},
+	}
+	var got bytes.Buffer
+	if ok, err := vault.Unlock(&got, unlockDrv); err != nil || !ok {
+		t.Fatalf("want unlock by node id, got %v, %v", ok, err)
+	}
+	for id, pos := range unlockDrv.positions {
+		if pos.Lock {
+			t.Errorf("want unlock position for node %x", id)
+		}
+	}
+	if !bytes.Equal(secret, got.Bytes()) {
+		t.Errorf("vault unlocked bad secret: want %v, got %v", secret, got.Bytes())
+	}
+}
+
+// contextDriver counts the contexts & releases of slow cryptex operations.
+type contextDriver struct {
+	test.Driver
+
+	contexts, stops *int
+}
+
+func (d contextDriver) Context() (context.Context, context.CancelFunc) {
+	*d.contexts++
+	ctx, cancel := context.WithCancel(context.Background())
+	return ctx, func() {
+		*d.stops++
+		cancel()
+	}
+}
+
+func (d contextDriver) Progress(cptx cryptex.Cryptex, done, total uint64) {}
+
+func TestVaultContextDriver(t *testing.T) {
+	plan, err := BuildPlan(bytes.NewBufferString(`
+root = delay
+
+[timelock "delay"]
+iterations = 1000
+bits = 512
+edge = puzzle
+
+[material "puzzle"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vault, secret := buildVault(plan, test.Driver{})
+
+	var contexts, stops int
+	drv := contextDriver{Driver: test.Driver{}, contexts: &contexts, stops: &stops}
+
+	var got bytes.Buffer
+	if ok, err := vault.Unlock(&got, drv); err != nil || !ok {
+		t.Fatalf("want unlocked timelock, got %v, %v", ok, err)
+	}
+	if !bytes.Equal(secret, got.Bytes()) {
+		t.Errorf("vault unlocked bad secret: want %v, got %v", secret, got.Bytes())
+	}
+	if contexts != 1 || stops != 1 {
+		t.Errorf("want 1 context released, got %d contexts & %d releases", contexts, stops)
+	}
+}
+
+func TestVaultStatus(t *testing.T) {
+	states := func(vault *Vault, db test.Driver) map[string]string {
+		statuses, err := vault.Status(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := map[string]string{}
+		for _, status := range statuses {
+			cmnt, err := status.Comment()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got[cmnt] = status.State.String()
+			if status.State == NodePending {
+				got[cmnt] = fmt.Sprintf("%d of %d", status.Solved, status.Required)
+			}
+		}
+		return got
+	}
+
+	op1, op2 := test.Driver{}, test.Driver{}
+	for _, db := range []test.Driver{op1, op2} {
+		if want, got := (map[string]string{
+			"master key":     "0 of 2",
+			"operator 1 key": "1 of 2",
+			"operator 2 key": "1 of 2",
+			"op 1 secret":    "missing secret",
+			"op 2 secret":    "missing secret",
+			"op 1 material":  "solved",
+			"op 2 material":  "solved",
+		}), states(twoManVault, db); !reflect.DeepEqual(want, got) {
+			t.Errorf("want states %v, got %v", want, got)
+		}
+	}
+
+	op1["op 1 secret"], op2["op 2 secret"] = twoManDriver["op 1 secret"], twoManDriver["op 2 secret"]
+	for _, db := range []test.Driver{op1, op2} {
+		if ok, err := twoManVault.Unlock(ioutil.Discard, skipDriver{Driver: db}); err != nil || ok {
+			t.Fatalf("want partial unlock, got %v, %v", ok, err)
+		}
+	}
+
+	got := states(twoManVault, op1)
+	if want := "solved"; want != got["operator 1 key"] || want != got["op 1 secret"] {
+		t.Errorf("want operator 1 key & secret %s, got %v", want, got)
+	}
+	if want := "1 of 2"; want != got["master key"] {
+		t.Errorf("want master key %s, got %s", want, got["master key"])
+	}
+
+	for id, data := range op2 {
+		op1[id] = data
+	}
+	if want, got := "ready", states(twoManVault, op1)["master key"]; want != got {
+		t.Errorf("want master key %s, got %s", want, got)
+	}
+
+	db := skipDriver{Driver: test.Driver{}}
+	for _, user := range []string{"alice", "bob"} {
+		key := test.Users[user].OpenPGPKey
+		db.Driver[key.KeyID] = mustOpenPGPKey(key.Private)
+	}
+	if ok, err := dnsSecVault.Unlock(ioutil.Discard, db); err != nil || ok {
+		t.Fatalf("want partial unlock, got %v, %v", ok, err)
+	}
+	if want, got := "2 of 5", states(dnsSecVault, db.Driver)["five-of-seven"]; want != got {
+		t.Errorf("want five-of-seven %s, got %s", want, got)
+	}
+
+	plan, err := BuildPlan(bytes.NewBufferString(`
+root = quorum
+
+[vss "quorum"]
+max-shares = 3
+required-shares = 2
+edge = a box
+edge = b box
+edge = c box
+edge = commitments
+
+[secretbox "a box"]
+edge = a
+edge = a material
+
+[secretbox "b box"]
+edge = b
+edge = b material
+
+[secretbox "c box"]
+edge = c
+edge = c material
+
+[password "a"]
+[password "b"]
+[password "c"]
+
+[material "a material"]
+[material "b material"]
+[material "c material"]
+[material "commitments"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lockDB := test.Driver{"a": []byte("a"), "b": []byte("b"), "c": []byte("c")}
+	vault, _ := buildVault(plan, lockDB)
+
+	// copy the material of the named nodes from the lock db
+	solvedDB := func(names ...string) test.Driver {
+		db := test.Driver{}
+		for _, node := range vault.Plan.Nodes {
+			cmnt, err := node.Comment()
+			if err != nil {
+				t.Fatal(err)
+			}
+			id, err := node.Digest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range names {
+				if name == cmnt {
+					db[string(id)] = lockDB[string(id)]
+				}
+			}
+		}
+		return db
+	}
+
+	// the commitments material counts as a required input
+	if want, got := "1 of 3", states(vault, solvedDB())["quorum"]; want != got {
+		t.Errorf("want quorum %s, got %s", want, got)
+	}
+	if want, got := "2 of 3", states(vault, solvedDB("a box"))["quorum"]; want != got {
+		t.Errorf("want quorum %s, got %s", want, got)
+	}
+	if want, got := "ready", states(vault, solvedDB("a box", "b box"))["quorum"]; want != got {
+		t.Errorf("want quorum %s, got %s", want, got)
+	}
+	if want, got := "ready", states(vault, solvedDB("a box", "b box", "c box"))["quorum"]; want != got {
+		t.Errorf("want quorum %s, got %s", want, got)
+	}
+}
@@ -1,9 +1,11 @@
 package vcrypt
 
 import (
+	"context"
 	"errors"
 	"io"
 
+	"github.com/vcrypt/vcrypt/cryptex"
 	"github.com/vcrypt/vcrypt/material"
 	"github.com/vcrypt/vcrypt/payload"
 	"github.com/vcrypt/vcrypt/seal"
@@ -11,11 +13,11 @@ import (
 )
 
 //go:generate -command protoc protoc --proto_path=$GOPATH/src:$GOPATH/src/github.com/gogo/protobuf/protobuf:. --gogo_out=.
-//go:generate protoc cryptex/cryptex.proto cryptex/sss.proto cryptex/xor.proto cryptex/secretbox.proto cryptex/box.proto cryptex/rsa.proto cryptex/openpgp.proto cryptex/mux.proto cryptex/demux.proto
+//go:generate protoc cryptex/cryptex.proto cryptex/sss.proto cryptex/xor.proto cryptex/secretbox.proto cryptex/box.proto cryptex/rsa.proto cryptex/openpgp.proto cryptex/mux.proto cryptex/demux.proto cryptex/ssh.proto cryptex/kdfsecretbox.proto cryptex/hybridkem.proto cryptex/age.proto cryptex/vss.proto cryptex/weightedsss.proto cryptex/threshold.proto cryptex/timelock.proto cryptex/derive.proto
 //go:generate protoc material/material.proto
-//go:generate protoc payload/payload.proto payload/attached.proto payload/detached.proto
-//go:generate protoc seal/seal.proto seal/openpgp.proto
-//go:generate protoc secret/secret.proto secret/password.proto secret/openpgpkey.proto secret/sshkey.proto
+//go:generate protoc payload/payload.proto payload/attached.proto payload/detached.proto payload/stream.proto
+//go:generate protoc seal/seal.proto seal/openpgp.proto seal/ed25519.proto seal/sshsig.proto
+//go:generate protoc secret/secret.proto secret/password.proto secret/openpgpkey.proto secret/sshkey.proto secret/sshagent.proto secret/x25519key.proto secret/hybridkemkey.proto secret/ageidentity.proto secret/pkcs11key.proto
 //go:generate protoc vcrypt.proto marker.proto node.proto plan.proto vault.proto
 
 // Driver is an interface for an interactive vault processor.
@@ -29,6 +31,45 @@ type Driver interface {
 	LoadSecret(secret.Secret) (data [][]byte, skip bool, err error)
 }
 
+// ContextDriver is an optional interface for a Driver that cancels & tracks
+// slow cryptex operations, such as opening a TimeLock.
+type ContextDriver interface {
+	Driver
+
+	// Context returns a context for a slow cryptex operation, and a func
+	// called to release it when the operation returns.
+	Context() (context.Context, context.CancelFunc)
+
+	// Progress reports the work done of the total for a cryptex.
+	Progress(cptx cryptex.Cryptex, done, total uint64)
+}
+
+// NodeDriver is an optional interface for a Driver that identifies a secret by
+// the node holding it instead of only by the secret comment.
+type NodeDriver interface {
+	Driver
+
+	// LoadNodeSecret returns the secret data for the Secret held by the node
+	// at the position.
+	LoadNodeSecret(sec secret.Secret, pos NodePosition) (data [][]byte, skip bool, err error)
+}
+
+// NodePosition locates a node in a plan graph.
+type NodePosition struct {
+	// ID is the node digest.
+	ID []byte
+
+	// Parents are the IDs of the nodes with an edge to the node.
+	Parents [][]byte
+
+	// Depth is the length of the shortest path from the root node.
+	Depth int
+
+	// Lock is set when the secret is loaded to lock the vault, in which case
+	// skipping it is an error.
+	Lock bool
+}
+
 // Sealer is an interface for the Seal method.
 type Sealer interface {
 	// Seal constructs a new seal for the data.